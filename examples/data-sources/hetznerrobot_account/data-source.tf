@@ -0,0 +1,5 @@
+data "hetznerrobot_account" "current" {}
+
+output "robot_endpoints" {
+  value = data.hetznerrobot_account.current.endpoints
+}
@@ -0,0 +1,3 @@
+data "hetznerrobot_firewall" "example" {
+  server_ip = "1.1.1.1"
+}
@@ -0,0 +1,32 @@
+data "hetznerrobot_firewall_rules" "example" {
+  intent {
+    name     = "SSH"
+    ports    = ["22"]
+    protocol = "tcp"
+    sources  = ["10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/23", "192.168.1.10"]
+  }
+
+  intent {
+    name     = "Web"
+    ports    = ["80", "443"]
+    protocol = "tcp"
+  }
+}
+
+resource "hetznerrobot_firewall" "example" {
+  server_ip     = "1.1.1.1"
+  active        = true
+  whitelist_hos = true
+
+  dynamic "rule" {
+    for_each = data.hetznerrobot_firewall_rules.example.rules
+    content {
+      name       = rule.value.name
+      src_ip     = rule.value.src_ip
+      dst_port   = rule.value.dst_port
+      protocol   = rule.value.protocol
+      action     = rule.value.action
+      ip_version = rule.value.ip_version
+    }
+  }
+}
@@ -0,0 +1,11 @@
+data "hetznerrobot_server_market_products" "storage" {
+  min_memory_size = 64
+  min_hdd_size    = 4096
+  min_hdd_count   = 4
+  datacenter      = "FSN1"
+  max_price       = 80
+}
+
+output "cheapest_storage_server" {
+  value = try(data.hetznerrobot_server_market_products.storage.products[0].id, null)
+}
@@ -1,3 +1,9 @@
 data "hetznerrobot_vswitch" "example" {
-  vswitch_id = 1234
+  id = 1234
+}
+
+# or look the vSwitch up by name and/or VLAN
+data "hetznerrobot_vswitch" "by_name" {
+  name = "private"
+  vlan = 4000
 }
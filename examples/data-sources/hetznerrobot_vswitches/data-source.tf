@@ -0,0 +1,5 @@
+data "hetznerrobot_vswitches" "all" {}
+
+output "vswitch_ids" {
+  value = [for vswitch in data.hetznerrobot_vswitches.all.vswitches : vswitch.id]
+}
@@ -11,6 +11,26 @@ terraform {
 provider "hetzner-robot" {
   username = "yourUserNameFromRobot"
   password = "yourPasswordFromRobot"
+
+  # or read the credentials from a profile, or from a password manager
+  # credentials_file   = "~/.config/hetznerrobot/credentials"
+  # profile            = "prod"
+  # credential_process = "pass show hetzner/robot-webservice-json"
+
+  # fail early on wrong credentials instead of in the first resource
+  # validate_credentials = true
+
+  # refuse every request that could change something, e.g. for audit pipelines
+  # read_only = true
+
+  # optional transport settings, e.g. behind a corporate egress proxy
+  # request_timeout = "60s"
+  # proxy_url       = "http://proxy.example.com:3128"
+  # ca_cert_file    = "/etc/ssl/certs/corporate-ca.pem"
+
+  # optional pacing to stay below Robot's hourly request limits on large fleets
+  # max_concurrent_requests = 5
+  # requests_per_minute     = 60
 }
 
 resource "hetznerrobot_firewall" "firewall" {
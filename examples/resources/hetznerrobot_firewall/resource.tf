@@ -2,6 +2,8 @@ resource "hetznerrobot_firewall" "example" {
   server_ip     = "1.1.1.1"
   active        = true
   whitelist_hos = true
+  filter_ipv6   = true
+  port          = "main"
 
   rule {
     name       = "Allow SSH"
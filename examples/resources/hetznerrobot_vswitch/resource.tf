@@ -2,6 +2,9 @@ resource "hetznerrobot_vswitch" "example" {
   name    = "example-vswitch"
   vlan_id = 100
 
+  # cancel at the end of the month instead of immediately on destroy
+  cancellation_date = "2026-12-31"
+
   server {
     server_ip = "1.1.1.1"
   }
@@ -0,0 +1,4 @@
+resource "hetznerrobot_vswitch_attachment" "example" {
+  vswitch_id    = 1234
+  server_number = 123456
+}
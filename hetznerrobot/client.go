@@ -2,33 +2,168 @@ package hetznerrobot
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"slices"
 	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tidwall/gjson"
 )
 
+// defaultRequestTimeout bounds a single Robot API call, including reading
+// the response body.
+const defaultRequestTimeout = 60 * time.Second
+
 type HetznerRobotClient struct {
 	username string
 	password string
 	url      string
+
+	// httpClient is shared by all copies of the client, so connections are
+	// reused across resources.
+	httpClient *http.Client
+	// limiter paces requests of all resources, nil disables it.
+	limiter *requestLimiter
+	// cache holds GET responses of all resources, nil disables it.
+	cache *responseCache
+	// readOnly rejects all requests except GET before they are sent.
+	readOnly bool
+}
+
+// HTTPClientOptions configures the transport used to talk to Robot.
+type HTTPClientOptions struct {
+	Timeout time.Duration
+	// ProxyURL overrides the HTTPS_PROXY/HTTP_PROXY/NO_PROXY environment.
+	ProxyURL string
+	// CACertPEM is trusted in addition to the system certificate pool.
+	CACertPEM          []byte
+	InsecureSkipVerify bool
 }
 
 func NewHetznerRobotClient(username string, password string, url string) HetznerRobotClient {
 	return HetznerRobotClient{
-		username: username,
-		password: password,
-		url:      url,
+		username:   username,
+		password:   password,
+		url:        url,
+		httpClient: &http.Client{Timeout: defaultRequestTimeout},
 	}
 }
 
+// NewHTTPClient returns an *http.Client for talking to Robot configured by
+// opts. A zero Timeout uses defaultRequestTimeout.
+func NewHTTPClient(opts HTTPClientOptions) (*http.Client, error) {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected default transport %T", http.DefaultTransport)
+	}
+	transport = transport.Clone()
+
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q: expected scheme://host[:port]", opts.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Only meant for local stand-ins of the Robot API
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+	if len(opts.CACertPEM) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(opts.CACertPEM) {
+			return nil, fmt.Errorf("no valid PEM certificates found in CA certificate")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = defaultRequestTimeout
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+// readCACert returns the PEM contents of caCertFile, or caCertPEM if no file
+// is given.
+func readCACert(caCertFile string, caCertPEM string) ([]byte, error) {
+	if caCertFile == "" {
+		return []byte(caCertPEM), nil
+	}
+	pem, err := os.ReadFile(caCertFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CA certificate: %w", err)
+	}
+	return pem, nil
+}
+
 func codeIsInExpected(statusCode int, expectedStatusCodes []int) bool {
 	return slices.Contains(expectedStatusCodes, statusCode)
 }
 
+// HetznerRobotAPIError is returned for responses with an unexpected status.
+type HetznerRobotAPIError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *HetznerRobotAPIError) Error() string {
+	return fmt.Sprintf("hetzner webservice response status %d: %s", e.StatusCode, e.Body)
+}
+
+// Code returns the error code Robot puts into JSON error bodies, e.g.
+// RATE_LIMIT_EXCEEDED, or an empty string.
+func (e *HetznerRobotAPIError) Code() string {
+	return gjson.GetBytes(e.Body, "error.code").String()
+}
+
+// errReadOnly is returned for requests that would change something while the
+// provider is configured with read_only.
+var errReadOnly = errors.New("the provider is configured with read_only = true")
+
 func (c *HetznerRobotClient) makeAPICall(ctx context.Context, method string, uri string, data url.Values, expectedStatusCodes []int) ([]byte, error) {
+	if c.readOnly && method != http.MethodGet {
+		return nil, fmt.Errorf("refusing %s %s: %w", method, strings.TrimPrefix(uri, c.url), errReadOnly)
+	}
+
+	if c.cache == nil {
+		return c.sendAPIRequest(ctx, method, uri, data, expectedStatusCodes)
+	}
+
+	path := strings.TrimPrefix(uri, c.url)
+	if method == http.MethodGet {
+		return c.cache.get(ctx, path, func() ([]byte, error) {
+			return c.sendAPIRequest(ctx, method, uri, data, expectedStatusCodes)
+		})
+	}
+
+	// failed writes may still have changed something
+	defer c.cache.invalidate(path)
+	return c.sendAPIRequest(ctx, method, uri, data, expectedStatusCodes)
+}
+
+func (c *HetznerRobotClient) sendAPIRequest(ctx context.Context, method string, uri string, data url.Values, expectedStatusCodes []int) ([]byte, error) {
 	var body io.Reader
 	if data != nil {
 		body = strings.NewReader(data.Encode())
@@ -44,10 +179,39 @@ func (c *HetznerRobotClient) makeAPICall(ctx context.Context, method string, uri
 
 	request.SetBasicAuth(c.username, c.password)
 
-	client := http.Client{}
+	client := c.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	ctx = c.withRedaction(ctx)
 
+	if c.limiter != nil {
+		release, err := c.limiter.wait(ctx, method, strings.TrimPrefix(uri, c.url))
+		if err != nil {
+			return nil, fmt.Errorf("error waiting for request limit: %w", err)
+		}
+		defer release()
+	}
+
+	fields := map[string]any{
+		"method": method,
+		"path":   request.URL.Path,
+	}
+	if data != nil {
+		fields["form"] = redactForm(data)
+	}
+	tflog.Debug(ctx, "Sending Robot API request", fields)
+
+	start := time.Now()
 	response, err := client.Do(request)
 	if err != nil {
+		tflog.Debug(ctx, "Robot API request failed", map[string]any{
+			"method":      method,
+			"path":        request.URL.Path,
+			"duration_ms": time.Since(start).Milliseconds(),
+			"error":       err.Error(),
+		})
 		return nil, fmt.Errorf("error sending request: %w", err)
 	}
 
@@ -58,8 +222,18 @@ func (c *HetznerRobotClient) makeAPICall(ctx context.Context, method string, uri
 		return nil, err
 	}
 
+	fields = map[string]any{
+		"method":      method,
+		"path":        request.URL.Path,
+		"status_code": response.StatusCode,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	tflog.Debug(ctx, "Received Robot API response", fields)
+	fields["response_body"] = redactResponseBody(responseBytes)
+	tflog.Trace(ctx, "Received Robot API response body", fields)
+
 	if !codeIsInExpected(response.StatusCode, expectedStatusCodes) {
-		return nil, fmt.Errorf("hetzner webservice response status %d: %s", response.StatusCode, responseBytes)
+		return nil, &HetznerRobotAPIError{StatusCode: response.StatusCode, Body: responseBytes}
 	}
 
 	return responseBytes, nil
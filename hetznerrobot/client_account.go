@@ -0,0 +1,50 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// accountEndpoints are the list endpoints probed to find out which parts of
+// the API the webservice user can reach.
+var accountEndpoints = []string{
+	"/server",
+	"/ip",
+	"/subnet",
+	"/failover",
+	"/key",
+	"/vswitch",
+	"/storagebox",
+}
+
+// errInvalidCredentials is returned by probeEndpoint when Robot rejects the
+// webservice user.
+var errInvalidCredentials = errors.New("invalid credentials")
+
+// probeEndpoint returns whether the webservice user may use the list
+// endpoint path. Robot answers 404 for lists without entries, which still
+// proves access.
+func (c *HetznerRobotClient) probeEndpoint(ctx context.Context, path string) (bool, error) {
+	_, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s%s", c.url, path), nil, []int{http.StatusOK, http.StatusNotFound})
+
+	var apiErr *HetznerRobotAPIError
+	switch {
+	case err == nil:
+		return true, nil
+	case !errors.As(err, &apiErr):
+		return false, err
+	case apiErr.StatusCode == http.StatusUnauthorized:
+		return false, errInvalidCredentials
+	case apiErr.StatusCode == http.StatusForbidden && apiErr.Code() != "RATE_LIMIT_EXCEEDED":
+		return false, nil
+	}
+	return false, err
+}
+
+// validateCredentials checks the credentials with a cheap request.
+func (c *HetznerRobotClient) validateCredentials(ctx context.Context) error {
+	_, err := c.probeEndpoint(ctx, "/server")
+	return err
+}
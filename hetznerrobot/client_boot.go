@@ -51,7 +51,8 @@ func (c *HetznerRobotClient) getBoot(ctx context.Context, serverID string) (*Boo
 	// bootProfile.AuthorizedKeys = gjson.Get(activeBoot, "authorized_keys").Array()
 	// bootProfile.HostKeys = gjson.Get(activeBoot, "host_keys").Array()
 	bootProfile.Password = gjson.Get(activeBoot, "password").String()
-	bootProfile.ServerID = int(gjson.Get(activeBoot, "server_num").Int())
+	// every configuration names the server, also while none is active
+	bootProfile.ServerID = int(gjson.Get(jsonStr, "boot.rescue.server_number").Int())
 	bootProfile.ServerIPv4 = gjson.Get(activeBoot, "server_ip").String()
 	bootProfile.ServerIPv6 = gjson.Get(activeBoot, "server_ipv6_net").String()
 
@@ -80,19 +81,16 @@ func (c *HetznerRobotClient) setBootProfile(ctx context.Context, serverID string
 		return nil, err
 	}
 
+	// the response only holds the configuration that was activated, e.g.
+	// {"rescue": {...}}, instead of all of them like GET /boot/{server-id}
 	jsonStr := string(bytes)
-	bootProfile := BootProfile{}
-	activeBoot := ""
-
-	if gjson.Get(jsonStr, "boot.linux.active").Bool() {
-		activeBoot = gjson.Get(jsonStr, "boot.linux").String()
-		bootProfile.ActiveProfile = "linux"
+	activeBoot := gjson.Get(jsonStr, activeBootProfile).String()
+	bootProfile := BootProfile{ActiveProfile: activeBootProfile}
+	switch activeBootProfile {
+	case "linux":
 		bootProfile.Language = gjson.Get(activeBoot, "lang").String()
 		bootProfile.OperatingSystem = gjson.Get(activeBoot, "dist").String()
-	}
-	if gjson.Get(jsonStr, "boot.rescue.active").Bool() {
-		activeBoot = gjson.Get(jsonStr, "boot.rescue").String()
-		bootProfile.ActiveProfile = "rescue"
+	case "rescue":
 		bootProfile.OperatingSystem = gjson.Get(activeBoot, "os").String()
 	}
 
@@ -100,9 +98,17 @@ func (c *HetznerRobotClient) setBootProfile(ctx context.Context, serverID string
 	// bootProfile.AuthorizedKeys = gjson.Get(activeBoot, "authorized_keys").Array()
 	// bootProfile.HostKeys = gjson.Get(activeBoot, "host_keys").Array()
 	bootProfile.Password = gjson.Get(activeBoot, "password").String()
-	bootProfile.ServerID = int(gjson.Get(activeBoot, "server_num").Int())
+	bootProfile.ServerID = int(gjson.Get(activeBoot, "server_number").Int())
 	bootProfile.ServerIPv4 = gjson.Get(activeBoot, "server_ip").String()
 	bootProfile.ServerIPv6 = gjson.Get(activeBoot, "server_ipv6_net").String()
 
 	return &bootProfile, nil
 }
+
+// deleteBootProfile deactivates a boot configuration. Robot only allows one
+// active configuration per server, so it has to be deactivated before a
+// different one can be activated.
+func (c *HetznerRobotClient) deleteBootProfile(ctx context.Context, serverID string, bootProfile string) error {
+	_, err := c.makeAPICall(ctx, "DELETE", fmt.Sprintf("%s/boot/%s/%s", c.url, serverID, bootProfile), nil, []int{http.StatusOK, http.StatusAccepted})
+	return err
+}
@@ -0,0 +1,51 @@
+package hetznerrobot
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/robotmock"
+)
+
+func newBootTestClient(t *testing.T) (*robotmock.Mock, HetznerRobotClient) {
+	t.Helper()
+
+	mock := robotmock.New(robotmock.Config{Username: "user", Password: "pass"})
+	mock.AddServer(robotmock.Server{Number: 1001, IP: "198.51.100.1"})
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	return mock, NewHetznerRobotClient("user", "pass", server.URL)
+}
+
+func TestGetBoot(t *testing.T) {
+	mock, client := newBootTestClient(t)
+	mock.SetBoot(1001, robotmock.Boot{Type: "rescue", OS: "linux", Arch: 64, Password: "secret"})
+
+	boot, err := client.getBoot(context.Background(), "1001")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if boot.ServerID != 1001 {
+		t.Fatalf("Expected server number 1001, got %d", boot.ServerID)
+	}
+	if boot.ActiveProfile != "rescue" || boot.OperatingSystem != "linux" || boot.Password != "secret" || boot.ServerIPv4 != "198.51.100.1" {
+		t.Fatalf("Unexpected boot configuration %+v", boot)
+	}
+}
+
+func TestSetBootProfile(t *testing.T) {
+	mock, client := newBootTestClient(t)
+
+	boot, err := client.setBootProfile(context.Background(), "1001", "rescue", "64", "linux", "", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// POST /boot/{server-number}/rescue only returns {"rescue": {...}}
+	if boot.ActiveProfile != "rescue" || boot.OperatingSystem != "linux" || boot.ServerID != 1001 || boot.ServerIPv4 != "198.51.100.1" {
+		t.Fatalf("Unexpected boot configuration %+v", boot)
+	}
+	if boot.Password == "" || boot.Password != mock.Boot(1001).Password {
+		t.Fatalf("Expected the generated password %q, got %q", mock.Boot(1001).Password, boot.Password)
+	}
+}
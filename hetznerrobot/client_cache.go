@@ -0,0 +1,127 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const defaultResponseCacheTTL = 30 * time.Second
+
+// responseCache keeps successful GET responses for ttl, so that resources
+// and data sources reading the same objects during one plan or apply share
+// a single request. Concurrent requests for the same path are deduplicated.
+// Any other request invalidates all entries below the same top level path,
+// e.g. a POST to /vswitch/1/server drops /vswitch and /vswitch/1.
+type responseCache struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	entries  map[string]cacheEntry
+	inflight map[string]*cacheCall
+	// generation is increased by every invalidation, responses of requests
+	// that started before are not stored
+	generation uint64
+}
+
+type cacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+type cacheCall struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+type skipResponseCacheKey struct{}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:      ttl,
+		entries:  map[string]cacheEntry{},
+		inflight: map[string]*cacheCall{},
+	}
+}
+
+// withoutResponseCache returns a context whose GET requests always go to
+// Robot, for polling loops waiting for a change. Their responses still
+// refresh the cache.
+func withoutResponseCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipResponseCacheKey{}, true)
+}
+
+// get returns the cached response for path, or calls fetch to get it.
+func (rc *responseCache) get(ctx context.Context, path string, fetch func() ([]byte, error)) ([]byte, error) {
+	skip, _ := ctx.Value(skipResponseCacheKey{}).(bool)
+
+	rc.mu.Lock()
+	if entry, ok := rc.entries[path]; ok && !skip && time.Now().Before(entry.expires) {
+		rc.mu.Unlock()
+		tflog.Debug(ctx, "Using cached Robot API response", map[string]any{"path": path})
+		return entry.body, nil
+	}
+	if call, ok := rc.inflight[path]; ok && !skip {
+		rc.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		tflog.Debug(ctx, "Using shared Robot API response", map[string]any{"path": path})
+		return call.body, call.err
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	if !skip {
+		rc.inflight[path] = call
+	}
+	generation := rc.generation
+	rc.mu.Unlock()
+
+	call.body, call.err = fetch()
+
+	rc.mu.Lock()
+	if rc.inflight[path] == call {
+		delete(rc.inflight, path)
+	}
+	if call.err == nil && generation == rc.generation {
+		rc.entries[path] = cacheEntry{body: call.body, expires: time.Now().Add(rc.ttl)}
+	}
+	rc.mu.Unlock()
+	close(call.done)
+
+	return call.body, call.err
+}
+
+// invalidate drops all entries below the top level path of path.
+func (rc *responseCache) invalidate(path string) {
+	prefix := responseCachePrefix(path)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.generation++
+	for key := range rc.entries {
+		if responseCachePrefix(key) == prefix {
+			delete(rc.entries, key)
+		}
+	}
+	// requests in flight may have read the old state
+	for key := range rc.inflight {
+		if responseCachePrefix(key) == prefix {
+			delete(rc.inflight, key)
+		}
+	}
+}
+
+// responseCachePrefix returns the top level path of path, e.g. /vswitch for
+// /vswitch/1/server?x=y.
+func responseCachePrefix(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return "/" + segment
+}
@@ -0,0 +1,186 @@
+package hetznerrobot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newResponseCacheTestServer(t *testing.T) (*httptest.Server, *sync.Map) {
+	t.Helper()
+
+	var requests sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count, _ := requests.LoadOrStore(r.Method+" "+r.URL.Path, new(atomic.Int32))
+		count.(*atomic.Int32).Add(1)
+		if r.URL.Path == "/boot/missing" {
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	return server, &requests
+}
+
+func requestCount(requests *sync.Map, key string) int32 {
+	count, ok := requests.Load(key)
+	if !ok {
+		return 0
+	}
+	return count.(*atomic.Int32).Load()
+}
+
+func TestResponseCache(t *testing.T) {
+	server, requests := newResponseCacheTestServer(t)
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	client.cache = newResponseCache(time.Hour)
+	ctx := context.Background()
+
+	get := func(path string) error {
+		_, err := client.makeAPICall(ctx, "GET", server.URL+path, nil, []int{http.StatusOK})
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := get("/vswitch/1"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := get("/firewall/1.2.3.4"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if count := requestCount(requests, "GET /vswitch/1"); count != 1 {
+		t.Fatalf("Expected 1 request for cached path, got %d", count)
+	}
+
+	// a write below /vswitch invalidates all of /vswitch but not /firewall
+	if _, err := client.makeAPICall(ctx, "POST", server.URL+"/vswitch/1/server", nil, []int{http.StatusOK}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := get("/vswitch/1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := get("/firewall/1.2.3.4"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count := requestCount(requests, "GET /vswitch/1"); count != 2 {
+		t.Fatalf("Expected write to invalidate /vswitch/1, got %d requests", count)
+	}
+	if count := requestCount(requests, "GET /firewall/1.2.3.4"); count != 1 {
+		t.Fatalf("Expected /firewall to stay cached, got %d requests", count)
+	}
+
+	// polling loops bypass the cache
+	if _, err := client.makeAPICall(withoutResponseCache(ctx), "GET", server.URL+"/vswitch/1", nil, []int{http.StatusOK}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count := requestCount(requests, "GET /vswitch/1"); count != 3 {
+		t.Fatalf("Expected uncached request, got %d requests", count)
+	}
+
+	// errors are not cached
+	for i := 0; i < 2; i++ {
+		if err := get("/boot/missing"); err == nil {
+			t.Fatal("Expected error but got none")
+		}
+	}
+	if count := requestCount(requests, "GET /boot/missing"); count != 2 {
+		t.Fatalf("Expected failed responses not to be cached, got %d requests", count)
+	}
+}
+
+func TestResponseCacheTTL(t *testing.T) {
+	server, requests := newResponseCacheTestServer(t)
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	client.cache = newResponseCache(20 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.makeAPICall(context.Background(), "GET", server.URL+"/server", nil, []int{http.StatusOK}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		time.Sleep(30 * time.Millisecond)
+	}
+	if count := requestCount(requests, "GET /server"); count != 2 {
+		t.Fatalf("Expected expired entry to be fetched again, got %d requests", count)
+	}
+}
+
+func TestResponseCacheDeduplicates(t *testing.T) {
+	release := make(chan struct{})
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		<-release
+		w.Write([]byte(`{"server": []}`))
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	client.cache = newResponseCache(time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := client.makeAPICall(context.Background(), "GET", server.URL+"/server", nil, []int{http.StatusOK})
+			if err != nil || string(body) != `{"server": []}` {
+				t.Errorf("Unexpected response %q, error: %v", body, err)
+			}
+		}()
+	}
+	// give all goroutines time to join the request in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if count.Load() != 1 {
+		t.Fatalf("Expected concurrent requests to be deduplicated, got %d requests", count.Load())
+	}
+}
+
+func TestResponseCacheInvalidatedWhileInFlight(t *testing.T) {
+	cache := newResponseCache(time.Hour)
+
+	// the write lands while the read is in flight, so its response may be stale
+	if _, err := cache.get(context.Background(), "/vswitch/1", func() ([]byte, error) {
+		cache.invalidate("/vswitch/1/server")
+		return []byte(`stale`), nil
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	body, err := cache.get(context.Background(), "/vswitch/1", func() ([]byte, error) {
+		return []byte(`fresh`), nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(body) != "fresh" {
+		t.Fatalf("Expected stale response not to be cached, got %s", body)
+	}
+}
+
+func TestResponseCachePrefix(t *testing.T) {
+	tests := map[string]string{
+		"/vswitch":           "/vswitch",
+		"/vswitch/1":         "/vswitch",
+		"/vswitch/1/server":  "/vswitch",
+		"/boot/1.2.3.4?x=/y": "/boot",
+		"/":                  "/",
+	}
+
+	for path, expected := range tests {
+		if prefix := responseCachePrefix(path); prefix != expected {
+			t.Fatalf("Expected prefix %s for %s, got %s", expected, path, prefix)
+		}
+	}
+}
@@ -6,21 +6,39 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
+const (
+	firewallStatusActive    = "active"
+	firewallStatusDisabled  = "disabled"
+	firewallStatusInProcess = "in process"
+
+	firewallPortMain = "main"
+	firewallPortKVM  = "kvm"
+)
+
+// firewallPollInterval is how often getFirewall is called while waiting for
+// Robot to apply a firewall change.
+var firewallPollInterval = 5 * time.Second
+
 type HetznerRobotFirewallResponse struct {
 	Firewall HetznerRobotFirewall `json:"firewall"`
 }
 
 type HetznerRobotFirewall struct {
 	IP                       string                    `json:"server_ip"`
+	ServerNumber             int                       `json:"server_number"`
 	WhitelistHetznerServices bool                      `json:"whitelist_hos"`
+	FilterIPv6               bool                      `json:"filter_ipv6"`
 	Status                   string                    `json:"status"`
+	Port                     string                    `json:"port"`
 	Rules                    HetznerRobotFirewallRules `json:"rules"`
 }
 
 type HetznerRobotFirewallRules struct {
-	Input []HetznerRobotFirewallRule `json:"input"`
+	Input  []HetznerRobotFirewallRule `json:"input"`
+	Output []HetznerRobotFirewallRule `json:"output"`
 }
 
 type HetznerRobotFirewallRule struct {
@@ -48,7 +66,7 @@ func (c *HetznerRobotClient) getFirewall(ctx context.Context, ip string) (*Hetzn
 	return &firewall.Firewall, nil
 }
 
-func (c *HetznerRobotClient) setFirewall(ctx context.Context, firewall HetznerRobotFirewall) error {
+func (c *HetznerRobotClient) setFirewall(ctx context.Context, firewall HetznerRobotFirewall) (*HetznerRobotFirewall, error) {
 	data := url.Values{}
 
 	whitelistHOS := "false"
@@ -56,8 +74,17 @@ func (c *HetznerRobotClient) setFirewall(ctx context.Context, firewall HetznerRo
 		whitelistHOS = "true"
 	}
 
+	filterIPv6 := "false"
+	if firewall.FilterIPv6 {
+		filterIPv6 = "true"
+	}
+
 	data.Set("whitelist_hos", whitelistHOS)
+	data.Set("filter_ipv6", filterIPv6)
 	data.Set("status", firewall.Status)
+	if firewall.Port != "" {
+		data.Set("port", firewall.Port)
+	}
 
 	// Process all rules using the working format
 	for idx, rule := range firewall.Rules.Input {
@@ -71,9 +98,13 @@ func (c *HetznerRobotClient) setFirewall(ctx context.Context, firewall HetznerRo
 		data.Set(fmt.Sprintf("rules[input][%d][ip_version]", idx), ipVersion)
 		data.Set(fmt.Sprintf("rules[input][%d][action]", idx), rule.Action)
 
-		// For IPv6 rules, src_ip and dst_ip CANNOT be set according to API restrictions
-		if ipVersion != "ipv6" {
-			// Only set IP addresses for IPv4 rules
+		// For IPv6 rules, src_ip and dst_ip CANNOT be set according to API
+		// restrictions, dropping them would open the rule to any address
+		if ipVersion == "ipv6" {
+			if rule.SrcIP != "" || rule.DstIP != "" {
+				return nil, fmt.Errorf("rule %d (%q): src_ip and dst_ip are not supported with ip_version ipv6", idx, rule.Name)
+			}
+		} else {
 			data.Set(fmt.Sprintf("rules[input][%d][src_ip]", idx), rule.SrcIP)
 			if rule.DstIP != "" {
 				data.Set(fmt.Sprintf("rules[input][%d][dst_ip]", idx), rule.DstIP)
@@ -99,10 +130,14 @@ func (c *HetznerRobotClient) setFirewall(ctx context.Context, firewall HetznerRo
 	data.Set("rules[output][0][name]", "Allow all")
 	data.Set("rules[output][0][action]", "accept")
 
-	_, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/firewall/%s", c.url, firewall.IP), data, []int{http.StatusOK, http.StatusAccepted})
+	bytes, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/firewall/%s", c.url, firewall.IP), data, []int{http.StatusOK, http.StatusAccepted})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	response := HetznerRobotFirewallResponse{}
+	if err = json.Unmarshal(bytes, &response); err != nil {
+		return nil, err
+	}
+	return &response.Firewall, nil
 }
@@ -0,0 +1,195 @@
+package hetznerrobot
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const defaultMaxConcurrentRequests = 5
+
+// robotEndpointLimits are the request limits Robot documents per endpoint.
+// Robot counts requests per hour and answers with 403 RATE_LIMIT_EXCEEDED
+// once a limit is reached, so requests beyond it are held back client side.
+var robotEndpointLimits = []struct {
+	method  string
+	prefix  string
+	perHour int
+}{
+	{method: "POST", prefix: "/reset", perHour: 50},
+	{prefix: "/reset", perHour: 500},
+	{prefix: "/boot", perHour: 500},
+	{prefix: "/firewall", perHour: 500},
+}
+
+// requestLimiter is shared by all copies of a HetznerRobotClient. It bounds
+// the number of requests in flight and paces requests so that no window
+// sees more requests than its limit.
+type requestLimiter struct {
+	concurrency chan struct{}
+
+	mu        sync.Mutex
+	global    *slidingWindow
+	endpoints []endpointWindow
+}
+
+type endpointWindow struct {
+	// method is empty for limits that apply to all methods
+	method string
+	prefix string
+	window *slidingWindow
+}
+
+// slidingWindow allows at most limit requests within any period of length
+// window.
+type slidingWindow struct {
+	limit  int
+	window time.Duration
+	// start times of the requests that have not left the window yet,
+	// oldest first. Requests held back by another window can be recorded
+	// out of order.
+	requests []time.Time
+}
+
+func newSlidingWindow(limit int, window time.Duration) *slidingWindow {
+	return &slidingWindow{limit: limit, window: window}
+}
+
+// next returns the earliest time at or after now that a request may start.
+// Requests that have left the window by now are forgotten.
+func (w *slidingWindow) next(now time.Time) time.Time {
+	expired := 0
+	for expired < len(w.requests) && !w.requests[expired].Add(w.window).After(now) {
+		expired++
+	}
+	w.requests = w.requests[expired:]
+
+	if len(w.requests) < w.limit {
+		return now
+	}
+	if at := w.requests[len(w.requests)-w.limit].Add(w.window); at.After(now) {
+		return at
+	}
+	return now
+}
+
+func (w *slidingWindow) record(at time.Time) {
+	i, _ := slices.BinarySearchFunc(w.requests, at, time.Time.Compare)
+	w.requests = slices.Insert(w.requests, i, at)
+}
+
+// remove forgets a request recorded at at, if it is still in the window.
+func (w *slidingWindow) remove(at time.Time) {
+	if i, found := slices.BinarySearchFunc(w.requests, at, time.Time.Compare); found {
+		w.requests = slices.Delete(w.requests, i, i+1)
+	}
+}
+
+// newRequestLimiter returns a limiter allowing maxConcurrent requests in
+// flight and requestsPerMinute requests per minute on top of Robot's
+// documented endpoint limits. A requestsPerMinute of 0 disables the global
+// pacing.
+func newRequestLimiter(maxConcurrent int, requestsPerMinute int) *requestLimiter {
+	var global *slidingWindow
+	if requestsPerMinute > 0 {
+		global = newSlidingWindow(requestsPerMinute, time.Minute)
+	}
+
+	endpoints := make([]endpointWindow, 0, len(robotEndpointLimits))
+	for _, limit := range robotEndpointLimits {
+		endpoints = append(endpoints, endpointWindow{
+			method: limit.method,
+			prefix: limit.prefix,
+			window: newSlidingWindow(limit.perHour, time.Hour),
+		})
+	}
+
+	return &requestLimiter{
+		concurrency: make(chan struct{}, max(maxConcurrent, 1)),
+		global:      global,
+		endpoints:   endpoints,
+	}
+}
+
+// wait blocks until a request to path may be sent and returns a function
+// that has to be called once the request is done. A request held back by a
+// limit does not take a concurrency slot while it waits, so unrelated
+// requests are not blocked behind it.
+func (l *requestLimiter) wait(ctx context.Context, method string, path string) (func(), error) {
+	at := l.reserve(time.Now(), method, path)
+	if delay := time.Until(at); delay > 0 {
+		tflog.Debug(ctx, "Waiting for Robot API request limit", map[string]any{
+			"method":   method,
+			"path":     path,
+			"delay_ms": delay.Milliseconds(),
+		})
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			l.unreserve(at, method, path)
+			return nil, ctx.Err()
+		}
+	}
+
+	select {
+	case l.concurrency <- struct{}{}:
+	case <-ctx.Done():
+		l.unreserve(at, method, path)
+		return nil, ctx.Err()
+	}
+	return func() { <-l.concurrency }, nil
+}
+
+// reserve returns the time a request to path may start at, and counts the
+// request against every window it falls into.
+func (l *requestLimiter) reserve(now time.Time, method string, path string) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	windows := l.windows(method, path)
+	at := now
+	for _, window := range windows {
+		if next := window.next(now); next.After(at) {
+			at = next
+		}
+	}
+	for _, window := range windows {
+		window.record(at)
+	}
+	return at
+}
+
+// unreserve gives back a reservation of a request that was not sent.
+func (l *requestLimiter) unreserve(at time.Time, method string, path string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, window := range l.windows(method, path) {
+		window.remove(at)
+	}
+}
+
+// windows returns every window a request to path falls into. l.mu must be
+// held.
+func (l *requestLimiter) windows(method string, path string) []*slidingWindow {
+	windows := make([]*slidingWindow, 0, len(l.endpoints)+1)
+	if l.global != nil {
+		windows = append(windows, l.global)
+	}
+	for _, endpoint := range l.endpoints {
+		if endpoint.method != "" && endpoint.method != method {
+			continue
+		}
+		if path != endpoint.prefix && !strings.HasPrefix(path, endpoint.prefix+"/") {
+			continue
+		}
+		windows = append(windows, endpoint.window)
+	}
+	return windows
+}
@@ -0,0 +1,230 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSlidingWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := newSlidingWindow(2, time.Minute)
+
+	for i, expected := range []time.Time{
+		start,
+		start,
+		start.Add(time.Minute),
+		start.Add(time.Minute),
+		start.Add(2 * time.Minute),
+	} {
+		at := window.next(start)
+		if !at.Equal(expected) {
+			t.Fatalf("Request %d: expected %v, got %v", i, expected, at)
+		}
+		window.record(at)
+	}
+
+	// requests are allowed again once the window has passed
+	if at := window.next(start.Add(3 * time.Minute)); !at.Equal(start.Add(3 * time.Minute)) {
+		t.Fatalf("Expected request to be allowed immediately, got %v", at)
+	}
+}
+
+func TestRequestLimiterReserve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newRequestLimiter(1, 0)
+
+	// POST /reset is limited to 50 per hour, GET /reset to 500
+	for i := 0; i < 50; i++ {
+		if at := limiter.reserve(now, "POST", "/reset/1.2.3.4"); !at.Equal(now) {
+			t.Fatalf("Request %d: expected no delay, got %v", i, at.Sub(now))
+		}
+	}
+	if at := limiter.reserve(now, "POST", "/reset/1.2.3.4"); !at.Equal(now.Add(time.Hour)) {
+		t.Fatalf("Expected 51st reset to wait an hour, got %v", at.Sub(now))
+	}
+	if at := limiter.reserve(now, "GET", "/reset/1.2.3.4"); !at.Equal(now) {
+		t.Fatalf("Expected GET /reset to be allowed, got %v", at.Sub(now))
+	}
+	if at := limiter.reserve(now, "GET", "/server/1"); !at.Equal(now) {
+		t.Fatalf("Expected GET /server to be unlimited, got %v", at.Sub(now))
+	}
+	if at := limiter.reserve(now, "GET", "/resetfoo"); !at.Equal(now) {
+		t.Fatalf("Expected prefix to only match whole path segments, got %v", at.Sub(now))
+	}
+}
+
+func TestRequestLimiterConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			observed := maxInFlight.Load()
+			if current <= observed || maxInFlight.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	client.limiter = newRequestLimiter(3, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.makeAPICall(context.Background(), "GET", server.URL+"/server", nil, []int{http.StatusOK}); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight.Load() > 3 {
+		t.Fatalf("Expected at most 3 requests in flight, got %d", maxInFlight.Load())
+	}
+	if maxInFlight.Load() < 2 {
+		t.Fatalf("Expected requests to run concurrently, got %d in flight", maxInFlight.Load())
+	}
+}
+
+func TestRequestLimiterPacing(t *testing.T) {
+	const window = 200 * time.Millisecond
+
+	var mu sync.Mutex
+	var resets, servers []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if r.URL.Path == "/server" {
+			servers = append(servers, time.Now())
+		} else {
+			resets = append(resets, time.Now())
+		}
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	client.limiter = &requestLimiter{
+		concurrency: make(chan struct{}, 20),
+		global:      newSlidingWindow(6, window),
+		endpoints: []endpointWindow{
+			{method: "POST", prefix: "/reset", window: newSlidingWindow(2, window)},
+		},
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 12; i++ {
+		method, path := "GET", "/server"
+		if i%3 == 0 {
+			method, path = "POST", "/reset/1.2.3.4"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.makeAPICall(context.Background(), method, server.URL+path, nil, []int{http.StatusOK}); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 12 requests at 6 per window need a second window, 4 resets at 2 per
+	// window need a second window as well
+	if elapsed := time.Since(start); elapsed < window {
+		t.Fatalf("Expected requests to be paced over at least %v, took %v", window, elapsed)
+	}
+
+	// allow for scheduling jitter between the limiter and the test server
+	const jitter = 20 * time.Millisecond
+	all := append(slices.Clone(resets), servers...)
+	slices.SortFunc(all, time.Time.Compare)
+	for i := 6; i < len(all); i++ {
+		if gap := all[i].Sub(all[i-6]); gap < window-jitter {
+			t.Fatalf("Expected at most 6 requests per %v, requests %d and %d were %v apart", window, i-6, i, gap)
+		}
+	}
+	slices.SortFunc(resets, time.Time.Compare)
+	for i := 2; i < len(resets); i++ {
+		if gap := resets[i].Sub(resets[i-2]); gap < window-jitter {
+			t.Fatalf("Expected at most 2 resets per %v, resets %d and %d were %v apart", window, i-2, i, gap)
+		}
+	}
+}
+
+func TestRequestLimiterCanceled(t *testing.T) {
+	limiter := &requestLimiter{
+		concurrency: make(chan struct{}, 1),
+		global:      newSlidingWindow(1, time.Hour),
+	}
+
+	release, err := limiter.wait(context.Background(), "GET", "/server")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.wait(ctx, "GET", "/server"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	// the canceled request gave its concurrency slot and its reservation back
+	if len(limiter.concurrency) != 0 {
+		t.Fatalf("Expected no requests in flight, got %d", len(limiter.concurrency))
+	}
+	if len(limiter.global.requests) != 1 {
+		t.Fatalf("Expected only the sent request to be counted, got %v", limiter.global.requests)
+	}
+}
+
+func TestRequestLimiterPacedDoesNotBlock(t *testing.T) {
+	limiter := &requestLimiter{
+		concurrency: make(chan struct{}, 1),
+		endpoints: []endpointWindow{
+			{method: "POST", prefix: "/reset", window: newSlidingWindow(1, time.Hour)},
+		},
+	}
+
+	release, err := limiter.wait(context.Background(), "POST", "/reset/1.2.3.4")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	release()
+
+	// the second reset is held back for an hour
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := limiter.wait(ctx, "POST", "/reset/1.2.3.4")
+		done <- err
+	}()
+
+	// an unrelated request still gets the only concurrency slot
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), time.Second)
+	defer waitCancel()
+	time.Sleep(10 * time.Millisecond)
+	release, err = limiter.wait(waitCtx, "GET", "/server")
+	if err != nil {
+		t.Fatalf("Expected unrelated request to proceed, got %v", err)
+	}
+	release()
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected canceled, got %v", err)
+	}
+}
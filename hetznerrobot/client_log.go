@@ -0,0 +1,83 @@
+package hetznerrobot
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const redactedValue = "***"
+
+// redactedFields are form fields and response keys that carry credentials or
+// SSH key material, e.g. the rescue system password returned by /boot.
+var redactedFields = []string{
+	"password",
+	"authorized_key",
+	"host_key",
+	"data",
+}
+
+// withRedaction masks the client credentials wherever they show up in log
+// messages or field values, as a safety net for what the explicit redaction
+// below misses.
+func (c *HetznerRobotClient) withRedaction(ctx context.Context) context.Context {
+	secrets := make([]string, 0, 1)
+	if c.password != "" {
+		secrets = append(secrets, c.password)
+	}
+	ctx = tflog.MaskAllFieldValuesStrings(ctx, secrets...)
+	ctx = tflog.MaskMessageStrings(ctx, secrets...)
+	return ctx
+}
+
+// redactForm returns the form fields of a request for logging.
+func redactForm(data url.Values) map[string]any {
+	result := make(map[string]any, len(data))
+	for key, values := range data {
+		if slices.Contains(redactedFields, key) {
+			result[key] = redactedValue
+			continue
+		}
+		if len(values) == 1 {
+			result[key] = values[0]
+			continue
+		}
+		result[key] = values
+	}
+	return result
+}
+
+// redactResponseBody returns a response body for logging. Bodies that are not
+// JSON are logged unchanged, Robot only returns those for errors.
+func redactResponseBody(body []byte) string {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+	redacted, err := json.Marshal(redactJSON(value))
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+func redactJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if slices.Contains(redactedFields, key) && item != nil {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactJSON(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactJSON(item)
+		}
+	}
+	return value
+}
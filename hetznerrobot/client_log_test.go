@@ -0,0 +1,115 @@
+package hetznerrobot
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestMakeAPICallLogging(t *testing.T) {
+	const (
+		username       = "robot-user"
+		password       = "robot-s3cret"
+		rescuePassword = "rescue-s3cret"
+		sshKey         = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKeyMaterial"
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"rescue": {"server_ip": "1.2.3.4", "os": "linux", "active": true, "password": "` + rescuePassword + `", "authorized_key": [{"key": {"fingerprint": "aa:bb", "data": "` + sshKey + `"}}], "host_key": []}}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	client := NewHetznerRobotClient(username, password, server.URL)
+	data := url.Values{}
+	data.Set("os", "linux")
+	data.Add("authorized_key", "aa:bb")
+	data.Set("password", password)
+	if _, err := client.makeAPICall(ctx, "POST", server.URL+"/boot/1.2.3.4/rescue", data, []int{http.StatusOK}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("Unable to decode log output: %v", err)
+	}
+
+	messages := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		messages = append(messages, entry["@message"].(string))
+	}
+	expectedMessages := []string{"Sending Robot API request", "Received Robot API response", "Received Robot API response body"}
+	if strings.Join(messages, "|") != strings.Join(expectedMessages, "|") {
+		t.Fatalf("Expected log messages %v, got %v", expectedMessages, messages)
+	}
+
+	request := entries[0]
+	if request["method"] != "POST" || request["path"] != "/boot/1.2.3.4/rescue" {
+		t.Fatalf("Unexpected request log entry: %v", request)
+	}
+	if form, _ := request["form"].(map[string]any); form["os"] != "linux" || form["authorized_key"] != redactedValue {
+		t.Fatalf("Unexpected form in request log entry: %v", request["form"])
+	}
+	response := entries[1]
+	if response["status_code"] != float64(http.StatusOK) || response["duration_ms"] == nil {
+		t.Fatalf("Unexpected response log entry: %v", response)
+	}
+	if body, _ := entries[2]["response_body"].(string); !strings.Contains(body, `"os":"linux"`) {
+		t.Fatalf("Expected response body in trace log entry, got: %v", entries[2])
+	}
+
+	log := output.String()
+	basicAuth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	for _, secret := range []string{password, rescuePassword, sshKey, "AAAAC3NzaC1lZDI1NTE5", "aa:bb", basicAuth} {
+		if strings.Contains(log, secret) {
+			t.Errorf("Secret %q leaked into log output:\n%s", secret, log)
+		}
+	}
+}
+
+func TestRedactResponseBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "nested password",
+			body:     `{"rescue": {"password": "secret", "active": true}}`,
+			expected: `{"rescue":{"active":true,"password":"***"}}`,
+		},
+		{
+			name:     "null password kept",
+			body:     `{"rescue": {"password": null}}`,
+			expected: `{"rescue":{"password":null}}`,
+		},
+		{
+			name:     "key list",
+			body:     `[{"key": {"name": "laptop", "data": "ssh-rsa AAAA"}}]`,
+			expected: `[{"key":{"data":"***","name":"laptop"}}]`,
+		},
+		{
+			name:     "not json",
+			body:     `Not Found`,
+			expected: `Not Found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := redactResponseBody([]byte(tt.body)); result != tt.expected {
+				t.Fatalf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
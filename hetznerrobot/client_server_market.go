@@ -0,0 +1,51 @@
+package hetznerrobot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type HetznerRobotServerMarketProductResponse struct {
+	Product HetznerRobotServerMarketProduct `json:"product"`
+}
+
+type HetznerRobotServerMarketProduct struct {
+	ID             int      `json:"id"`
+	Name           string   `json:"name"`
+	Description    []string `json:"description"`
+	Traffic        string   `json:"traffic"`
+	Dist           []string `json:"dist"`
+	CPU            string   `json:"cpu"`
+	CPUBenchmark   int      `json:"cpu_benchmark"`
+	MemorySize     int      `json:"memory_size"`
+	HDDSize        int      `json:"hdd_size"`
+	HDDText        string   `json:"hdd_text"`
+	HDDCount       int      `json:"hdd_count"`
+	Datacenter     string   `json:"datacenter"`
+	NetworkSpeed   string   `json:"network_speed"`
+	FixedPrice     bool     `json:"fixed_price"`
+	NextReduce     int      `json:"next_reduce"`
+	NextReduceDate string   `json:"next_reduce_date"`
+	// Robot returns prices as decimal strings, e.g. "39.0000"
+	Price      json.Number `json:"price"`
+	PriceSetup json.Number `json:"price_setup"`
+}
+
+func (c *HetznerRobotClient) getServerMarketProducts(ctx context.Context) ([]HetznerRobotServerMarketProduct, error) {
+	res, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/order/server_market/product", c.url), nil, []int{http.StatusOK})
+	if err != nil {
+		return nil, err
+	}
+
+	productsResponse := []HetznerRobotServerMarketProductResponse{}
+	if err = json.Unmarshal(res, &productsResponse); err != nil {
+		return nil, err
+	}
+	products := make([]HetznerRobotServerMarketProduct, 0, len(productsResponse))
+	for _, product := range productsResponse {
+		products = append(products, product.Product)
+	}
+	return products, nil
+}
@@ -3,12 +3,16 @@ package hetznerrobot
 import (
 	"context"
 	"encoding/base64"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewHetznerRobotClient(t *testing.T) {
@@ -353,3 +357,262 @@ func TestMakeAPICallExpectedStatusCodes(t *testing.T) {
 		})
 	}
 }
+
+func TestSetFirewallFormEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		firewall HetznerRobotFirewall
+		expected map[string]string
+		absent   []string
+	}{
+		{
+			name: "kvm port with ipv6 filtering",
+			firewall: HetznerRobotFirewall{
+				IP:                       "1.2.3.4",
+				WhitelistHetznerServices: true,
+				FilterIPv6:               true,
+				Status:                   "active",
+				Port:                     "kvm",
+				Rules: HetznerRobotFirewallRules{Input: []HetznerRobotFirewallRule{
+					{Name: "SSH", SrcIP: "10.0.0.0/8", DstPort: "22", Protocol: "tcp", Action: "accept", IPVersion: "ipv4"},
+				}},
+			},
+			expected: map[string]string{
+				"status":                      "active",
+				"whitelist_hos":               "true",
+				"filter_ipv6":                 "true",
+				"port":                        "kvm",
+				"rules[input][0][name]":       "SSH",
+				"rules[input][0][src_ip]":     "10.0.0.0/8",
+				"rules[input][0][dst_port]":   "22",
+				"rules[input][0][protocol]":   "tcp",
+				"rules[input][0][action]":     "accept",
+				"rules[input][0][ip_version]": "ipv4",
+				"rules[output][0][action]":    "accept",
+			},
+		},
+		{
+			name: "main port without ipv6 filtering",
+			firewall: HetznerRobotFirewall{
+				IP:     "1.2.3.4",
+				Status: "disabled",
+				Port:   "main",
+			},
+			expected: map[string]string{
+				"status":        "disabled",
+				"whitelist_hos": "false",
+				"filter_ipv6":   "false",
+				"port":          "main",
+			},
+		},
+		{
+			name: "port omitted",
+			firewall: HetznerRobotFirewall{
+				IP:     "1.2.3.4",
+				Status: "active",
+			},
+			expected: map[string]string{
+				"filter_ipv6": "false",
+			},
+			absent: []string{"port"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var form url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "POST" || r.URL.Path != "/firewall/1.2.3.4" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("Failed to parse form: %v", err)
+				}
+				form = r.PostForm
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client := NewHetznerRobotClient("user", "pass", server.URL)
+			if _, err := client.setFirewall(context.Background(), tt.firewall); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for key, value := range tt.expected {
+				if form.Get(key) != value {
+					t.Errorf("Expected %s=%s, got %q", key, value, form.Get(key))
+				}
+			}
+			for _, key := range tt.absent {
+				if form.Has(key) {
+					t.Errorf("Expected %s to be absent, got %q", key, form.Get(key))
+				}
+			}
+		})
+	}
+}
+
+func TestSetFirewallIPv6Addresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	_, err := client.setFirewall(context.Background(), HetznerRobotFirewall{
+		IP:     "1.2.3.4",
+		Status: "active",
+		Rules: HetznerRobotFirewallRules{Input: []HetznerRobotFirewallRule{
+			{Name: "SSH", SrcIP: "2001:db8::/32", DstPort: "22", Protocol: "tcp", Action: "accept", IPVersion: "ipv6"},
+		}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "not supported with ip_version ipv6") {
+		t.Fatalf("Expected error for src_ip on an IPv6 rule, got %v", err)
+	}
+}
+
+func testServerCACertPEM(t *testing.T, server *httptest.Server) []byte {
+	t.Helper()
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+func TestHTTPClientTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		opts      HTTPClientOptions
+		expectErr bool
+	}{
+		{
+			name:      "untrusted certificate",
+			opts:      HTTPClientOptions{},
+			expectErr: true,
+		},
+		{
+			name: "custom CA",
+			opts: HTTPClientOptions{CACertPEM: testServerCACertPEM(t, server)},
+		},
+		{
+			name: "insecure skip verify",
+			opts: HTTPClientOptions{InsecureSkipVerify: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient, err := NewHTTPClient(tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			client := NewHetznerRobotClient("user", "pass", server.URL)
+			client.httpClient = httpClient
+
+			_, err = client.makeAPICall(context.Background(), "GET", server.URL+"/server", nil, []int{http.StatusOK})
+			if tt.expectErr && err == nil {
+				t.Fatal("Expected error but got none")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestHTTPClientInvalidCACert(t *testing.T) {
+	if _, err := NewHTTPClient(HTTPClientOptions{CACertPEM: []byte("not a certificate")}); err == nil {
+		t.Fatal("Expected error for invalid CA certificate")
+	}
+}
+
+func TestHTTPClientProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a forward proxy receives the absolute URL of the target
+		proxied = append(proxied, r.URL.String())
+		if _, _, ok := r.BasicAuth(); !ok {
+			t.Errorf("Expected basic auth to be forwarded")
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer proxy.Close()
+
+	for _, proxyURL := range []string{"robot-proxy:3128", "://invalid"} {
+		if _, err := NewHTTPClient(HTTPClientOptions{ProxyURL: proxyURL}); err == nil {
+			t.Fatalf("Expected error for proxy URL %q", proxyURL)
+		}
+	}
+
+	httpClient, err := NewHTTPClient(HTTPClientOptions{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client := NewHetznerRobotClient("user", "pass", "http://robot.invalid")
+	client.httpClient = httpClient
+
+	if _, err := client.makeAPICall(context.Background(), "GET", "http://robot.invalid/server", nil, []int{http.StatusOK}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(proxied) != 1 || proxied[0] != "http://robot.invalid/server" {
+		t.Fatalf("Expected request to be sent through the proxy, got %v", proxied)
+	}
+}
+
+func TestHTTPClientTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	httpClient, err := NewHTTPClient(HTTPClientOptions{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	client.httpClient = httpClient
+
+	_, err = client.makeAPICall(context.Background(), "GET", server.URL+"/server", nil, []int{http.StatusOK})
+	if err == nil {
+		t.Fatal("Expected timeout error but got none")
+	}
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("Expected timeout error, got: %v", err)
+	}
+}
+
+func TestMakeAPICallReadOnly(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	client.readOnly = true
+
+	if _, err := client.makeAPICall(context.Background(), "GET", server.URL+"/firewall/1.2.3.4", nil, []int{http.StatusOK}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, method := range []string{"POST", "PUT", "DELETE"} {
+		_, err := client.makeAPICall(context.Background(), method, server.URL+"/firewall/1.2.3.4", url.Values{"status": {"disabled"}}, []int{http.StatusOK})
+		if !errors.Is(err, errReadOnly) {
+			t.Fatalf("Expected read only error for %s, got: %v", method, err)
+		}
+		if !strings.Contains(err.Error(), method+" /firewall/1.2.3.4") {
+			t.Fatalf("Expected error to name the request, got: %v", err)
+		}
+	}
+
+	if len(methods) != 1 || methods[0] != "GET" {
+		t.Fatalf("Expected only the GET request to reach the server, got %v", methods)
+	}
+}
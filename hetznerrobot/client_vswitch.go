@@ -10,6 +10,24 @@ import (
 	"time"
 )
 
+const (
+	vSwitchServerStatusReady     = "ready"
+	vSwitchServerStatusInProcess = "in process"
+	vSwitchServerStatusFailed    = "failed"
+
+	vSwitchCancellationNow        = "now"
+	vSwitchCancellationDateFormat = "2006-01-02"
+)
+
+// vSwitchServerBatchSize is the number of servers sent per request when
+// attaching or detaching servers, to keep single requests well below what
+// Robot accepts for a vSwitch.
+const vSwitchServerBatchSize = 10
+
+// vSwitchPollInterval is how often getVSwitch is called while waiting for
+// Robot to finish attaching or detaching servers.
+var vSwitchPollInterval = 5 * time.Second
+
 type HetznerRobotVSwitchServer struct {
 	ServerNumber  int    `json:"server_number,omitempty"`
 	ServerIP      string `json:"server_ip,omitempty"`
@@ -40,6 +58,21 @@ type HetznerRobotVSwitch struct {
 	CloudNetwork []HetznerRobotVSwitchCloudNetwork `json:"cloud_network"`
 }
 
+// getVSwitches lists all vSwitches of the account. The list entries carry no
+// servers, subnets or cloud networks, use getVSwitch for those.
+func (c *HetznerRobotClient) getVSwitches(ctx context.Context) ([]HetznerRobotVSwitch, error) {
+	res, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/vswitch", c.url), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	vSwitches := []HetznerRobotVSwitch{}
+	if err = json.Unmarshal(res, &vSwitches); err != nil {
+		return nil, err
+	}
+	return vSwitches, nil
+}
+
 func (c *HetznerRobotClient) getVSwitch(ctx context.Context, id string) (*HetznerRobotVSwitch, error) {
 	res, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/vswitch/%s", c.url, id), nil, []int{http.StatusOK, http.StatusAccepted})
 	if err != nil {
@@ -57,7 +90,7 @@ func (c *HetznerRobotClient) createVSwitch(ctx context.Context, name string, vla
 	data := url.Values{}
 	data.Set("vlan", strconv.Itoa(vlan))
 	data.Set("name", name)
-	res, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/vswitch", c.url), data, []int{http.StatusOK, http.StatusAccepted})
+	res, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/vswitch", c.url), data, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +106,7 @@ func (c *HetznerRobotClient) updateVSwitch(ctx context.Context, id string, name
 	data := url.Values{}
 	data.Set("vlan", strconv.Itoa(vlan))
 	data.Set("name", name)
-	_, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/vswitch/%s", c.url, id), data, []int{http.StatusOK, http.StatusAccepted})
+	_, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/vswitch/%s", c.url, id), data, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
 	if err != nil {
 		return err
 	}
@@ -85,7 +118,7 @@ func (c *HetznerRobotClient) addVSwitchServers(ctx context.Context, id string, s
 	for _, server := range servers {
 		data.Add("server", strconv.Itoa(server.ServerNumber))
 	}
-	_, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/vswitch/%s/server", c.url, id), data, []int{http.StatusOK, http.StatusAccepted})
+	_, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/vswitch/%s/server", c.url, id), data, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
 	if err != nil {
 		return err
 	}
@@ -104,10 +137,14 @@ func (c *HetznerRobotClient) removeVSwitchServers(ctx context.Context, id string
 	return nil
 }
 
-func (c *HetznerRobotClient) deleteVSwitch(ctx context.Context, id string) error {
-	now := time.Now()
+// deleteVSwitch cancels the vSwitch on cancellationDate, which is either
+// formatted as vSwitchCancellationDateFormat or vSwitchCancellationNow.
+func (c *HetznerRobotClient) deleteVSwitch(ctx context.Context, id string, cancellationDate string) error {
+	if cancellationDate == "" {
+		cancellationDate = vSwitchCancellationNow
+	}
 	data := url.Values{}
-	data.Set("cancellation_date", now.Format("2006-01-02"))
+	data.Set("cancellation_date", cancellationDate)
 	_, err := c.makeAPICall(ctx, "DELETE", fmt.Sprintf("%s/vswitch/%s", c.url, id), data, []int{http.StatusOK, http.StatusAccepted})
 	if err != nil {
 		return err
@@ -0,0 +1,40 @@
+package hetznerrobot
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/robotmock"
+)
+
+func TestVSwitchCreated(t *testing.T) {
+	mock := robotmock.New(robotmock.Config{Username: "user", Password: "pass"})
+	mock.AddServer(robotmock.Server{Number: 1001, IP: "198.51.100.1"})
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	ctx := context.Background()
+
+	// Robot answers 201 Created when creating, updating and attaching
+	vSwitch, err := client.createVSwitch(ctx, "test", 4000)
+	if err != nil {
+		t.Fatalf("Unexpected error creating: %v", err)
+	}
+	id := strconv.Itoa(vSwitch.ID)
+	if err := client.updateVSwitch(ctx, id, "renamed", 4001); err != nil {
+		t.Fatalf("Unexpected error updating: %v", err)
+	}
+	if err := client.addVSwitchServers(ctx, id, []HetznerRobotVSwitchServer{{ServerNumber: 1001}}); err != nil {
+		t.Fatalf("Unexpected error adding servers: %v", err)
+	}
+
+	got, ok := mock.VSwitch(vSwitch.ID)
+	if !ok {
+		t.Fatalf("Expected vSwitch %d to exist", vSwitch.ID)
+	}
+	if got.Name != "renamed" || got.VLAN != 4001 || len(got.Servers) != 1 {
+		t.Fatalf("Unexpected vSwitch %+v", got)
+	}
+}
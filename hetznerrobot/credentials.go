@@ -0,0 +1,175 @@
+package hetznerrobot
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultCredentialsProfile = "default"
+
+	// credentialProcessTimeout bounds how long a credential process may
+	// take, e.g. while a password manager waits to be unlocked.
+	credentialProcessTimeout = 2 * time.Minute
+)
+
+// credentials are the Robot webservice credentials of a profile. Profiles
+// can name a credential process instead of storing the password.
+type credentials struct {
+	Username          string `json:"username" yaml:"username"`
+	Password          string `json:"password" yaml:"password"`
+	CredentialProcess string `json:"-" yaml:"credential_process"`
+}
+
+// resolveCredentials completes username and password, which come from the
+// provider configuration or environment and take precedence, with the
+// output of credentialProcess or the profile in credentialsFile.
+func resolveCredentials(ctx context.Context, username, password, credentialsFile, profile, credentialProcess string) (string, string, error) {
+	if username != "" && password != "" {
+		return username, password, nil
+	}
+
+	resolved := credentials{CredentialProcess: credentialProcess}
+	if credentialProcess == "" && credentialsFile != "" {
+		var err error
+		resolved, err = loadCredentialsProfile(credentialsFile, profile)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if resolved.CredentialProcess != "" {
+		fromProcess, err := runCredentialProcess(ctx, resolved.CredentialProcess)
+		if err != nil {
+			return "", "", err
+		}
+		resolved.Username = cmp.Or(fromProcess.Username, resolved.Username)
+		resolved.Password = cmp.Or(fromProcess.Password, resolved.Password)
+	}
+
+	return cmp.Or(username, resolved.Username), cmp.Or(password, resolved.Password), nil
+}
+
+// loadCredentialsProfile reads profile from a credentials file, where a
+// leading ~/ stands for the home directory. Files ending
+// in .yaml or .yml hold a mapping of profile names, all others are INI files
+// with one section per profile.
+func loadCredentialsProfile(path, profile string) (credentials, error) {
+	if profile == "" {
+		profile = defaultCredentialsProfile
+	}
+
+	if rest, found := strings.CutPrefix(path, "~/"); found {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return credentials{}, fmt.Errorf("unable to expand credentials file path: %w", err)
+		}
+		path = filepath.Join(home, rest)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return credentials{}, fmt.Errorf("unable to read credentials file: %w", err)
+	}
+
+	var profiles map[string]credentials
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(content, &profiles); err != nil {
+			return credentials{}, fmt.Errorf("unable to parse credentials file %s: %w", path, err)
+		}
+	default:
+		profiles, err = parseCredentialsINI(content)
+		if err != nil {
+			return credentials{}, fmt.Errorf("unable to parse credentials file %s: %w", path, err)
+		}
+	}
+
+	result, ok := profiles[profile]
+	if !ok {
+		return credentials{}, fmt.Errorf("profile %q not found in credentials file %s", profile, path)
+	}
+	return result, nil
+}
+
+func parseCredentialsINI(content []byte) (map[string]credentials, error) {
+	profiles := map[string]credentials{}
+	section := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			profiles[section] = credentials{}
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found || section == "" {
+			return nil, fmt.Errorf("line %d: expected [profile] or key = value", lineNumber)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		profileCredentials := profiles[section]
+		switch key {
+		case "username":
+			profileCredentials.Username = value
+		case "password":
+			profileCredentials.Password = value
+		case "credential_process":
+			profileCredentials.CredentialProcess = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown key %q", lineNumber, key)
+		}
+		profiles[section] = profileCredentials
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// runCredentialProcess runs command through the shell and reads the
+// credentials from the JSON object it prints, e.g.
+// {"username": "#ws+abc", "password": "secret"}.
+func runCredentialProcess(ctx context.Context, command string) (credentials, error) {
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// the output holds the password, so it is never part of an error
+	if err := cmd.Run(); err != nil {
+		return credentials{}, fmt.Errorf("credential process failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var result credentials
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return credentials{}, fmt.Errorf("credential process did not print a JSON object with username and password")
+	}
+	return result, nil
+}
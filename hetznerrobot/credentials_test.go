@@ -0,0 +1,165 @@
+package hetznerrobot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func writeCredentialsFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return path
+}
+
+func TestLoadCredentialsProfile(t *testing.T) {
+	iniFile := writeCredentialsFile(t, "credentials", `
+# Robot webservice users
+[default]
+username = default-user
+password = default-pass
+
+[prod]
+username = prod-user
+credential_process = pass show robot/prod
+`)
+	yamlFile := writeCredentialsFile(t, "credentials.yaml", `
+default:
+  username: default-user
+  password: default-pass
+prod:
+  username: prod-user
+  credential_process: pass show robot/prod
+`)
+
+	for _, path := range []string{iniFile, yamlFile} {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			result, err := loadCredentialsProfile(path, "")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != (credentials{Username: "default-user", Password: "default-pass"}) {
+				t.Fatalf("Unexpected default profile: %+v", result)
+			}
+
+			result, err = loadCredentialsProfile(path, "prod")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != (credentials{Username: "prod-user", CredentialProcess: "pass show robot/prod"}) {
+				t.Fatalf("Unexpected prod profile: %+v", result)
+			}
+
+			if _, err := loadCredentialsProfile(path, "missing"); err == nil || !strings.Contains(err.Error(), `profile "missing" not found`) {
+				t.Fatalf("Expected missing profile error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseCredentialsINIInvalid(t *testing.T) {
+	for _, content := range []string{
+		"username = outside-of-profile",
+		"[default]\nusername",
+		"[default]\ntoken = abc",
+	} {
+		if _, err := parseCredentialsINI([]byte(content)); err == nil {
+			t.Fatalf("Expected error for %q", content)
+		}
+	}
+}
+
+func TestResolveCredentials(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential process tests use a POSIX shell")
+	}
+
+	credentialsFile := writeCredentialsFile(t, "credentials", `
+[default]
+username = file-user
+password = file-pass
+
+[process]
+username = file-user
+credential_process = echo '{"password": "process-pass"}'
+`)
+
+	tests := []struct {
+		name              string
+		username          string
+		password          string
+		profile           string
+		credentialProcess string
+		expectedUsername  string
+		expectedPassword  string
+		expectErr         string
+	}{
+		{
+			name:             "configured credentials win",
+			username:         "config-user",
+			password:         "config-pass",
+			expectedUsername: "config-user",
+			expectedPassword: "config-pass",
+		},
+		{
+			name:             "credentials file",
+			expectedUsername: "file-user",
+			expectedPassword: "file-pass",
+		},
+		{
+			name:             "configured username with password from file",
+			username:         "config-user",
+			expectedUsername: "config-user",
+			expectedPassword: "file-pass",
+		},
+		{
+			name:             "profile with credential process",
+			profile:          "process",
+			expectedUsername: "file-user",
+			expectedPassword: "process-pass",
+		},
+		{
+			name:              "credential process",
+			credentialProcess: `printf '{"username": "process-user", "password": "process-pass"}'`,
+			expectedUsername:  "process-user",
+			expectedPassword:  "process-pass",
+		},
+		{
+			name:              "failing credential process",
+			credentialProcess: `echo locked >&2; exit 1`,
+			expectErr:         "credential process failed: exit status 1: locked",
+		},
+		{
+			name:              "credential process without json",
+			credentialProcess: `echo secret`,
+			expectErr:         "credential process did not print a JSON object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			username, password, err := resolveCredentials(context.Background(), tt.username, tt.password, credentialsFile, tt.profile, tt.credentialProcess)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("Expected error containing %q, got: %v", tt.expectErr, err)
+				}
+				if strings.Contains(err.Error(), "secret") {
+					t.Fatalf("Credential process output leaked into error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if username != tt.expectedUsername || password != tt.expectedPassword {
+				t.Fatalf("Expected %s/%s, got %s/%s", tt.expectedUsername, tt.expectedPassword, username, password)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataAccount() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAccountRead,
+		Description: "Provides the Hetzner Robot webservice user the provider is authenticated as",
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Webservice user",
+			},
+			"url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Robot webservice URL",
+			},
+			"endpoints": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "API endpoints the webservice user can reach, e.g. \"/server\" or \"/vswitch\"",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAccountRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	c, ok := meta.(HetznerRobotClient)
+	if !ok {
+		return diag.Errorf("Unable to cast meta to HetznerRobotClient")
+	}
+
+	endpoints := make([]string, 0, len(accountEndpoints))
+	for _, endpoint := range accountEndpoints {
+		reachable, err := c.probeEndpoint(ctx, endpoint)
+		if errors.Is(err, errInvalidCredentials) {
+			return diag.Errorf("Robot rejected the webservice user %q", c.username)
+		}
+		if err != nil {
+			return diag.Errorf("Unable to probe endpoint %s:\n\t %q", endpoint, err)
+		}
+		if reachable {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	if err := d.Set("username", c.username); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("url", c.url); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("endpoints", endpoints); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(c.username)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	return diags
+}
@@ -0,0 +1,100 @@
+package hetznerrobot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func newAccountTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, _ := r.BasicAuth(); username != "#ws+user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"status": 401, "code": "UNAUTHORIZED", "message": "Unauthorized"}}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/server", "/vswitch":
+			w.Write([]byte(`[]`))
+		case "/storagebox":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": {"status": 403, "code": "FORBIDDEN", "message": "Forbidden"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"status": 404, "code": "NOT_FOUND", "message": "Not found"}}`))
+		}
+	}))
+}
+
+func TestDataSourceAccountRead(t *testing.T) {
+	server := newAccountTestServer(t)
+	defer server.Close()
+
+	client := NewHetznerRobotClient("#ws+user", "pass", server.URL)
+	d := schema.TestResourceDataRaw(t, dataAccount().Schema, map[string]any{})
+	if diags := dataSourceAccountRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	if d.Id() != "#ws+user" || d.Get("username").(string) != "#ws+user" {
+		t.Fatalf("Expected webservice user #ws+user, got ID %s, username %v", d.Id(), d.Get("username"))
+	}
+	if d.Get("url").(string) != server.URL {
+		t.Fatalf("Expected url %s, got %v", server.URL, d.Get("url"))
+	}
+
+	endpoints := make([]string, 0)
+	for _, endpoint := range d.Get("endpoints").([]any) {
+		endpoints = append(endpoints, endpoint.(string))
+	}
+	expected := []string{"/server", "/ip", "/subnet", "/failover", "/key", "/vswitch"}
+	if !slices.Equal(endpoints, expected) {
+		t.Fatalf("Expected endpoints %v, got %v", expected, endpoints)
+	}
+
+	wrongClient := NewHetznerRobotClient("#ws+user", "wrong", server.URL)
+	d = schema.TestResourceDataRaw(t, dataAccount().Schema, map[string]any{})
+	diags := dataSourceAccountRead(context.Background(), d, wrongClient)
+	if !diags.HasError() || diags[0].Summary != `Robot rejected the webservice user "#ws+user"` {
+		t.Fatalf("Expected invalid credentials error, got: %v", diags)
+	}
+}
+
+func TestProbeEndpointRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"status": 403, "code": "RATE_LIMIT_EXCEEDED", "message": "Rate limit exceeded"}}`))
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	if _, err := client.probeEndpoint(context.Background(), "/server"); err == nil {
+		t.Fatal("Expected rate limit to be reported as error")
+	}
+}
+
+func TestAccDataSourceAccount(t *testing.T) {
+	_, providerConfig := testAccRobotMock(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "hetznerrobot_account" "test" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetznerrobot_account.test", "username", "acc-user"),
+					resource.TestCheckTypeSetElemAttr("data.hetznerrobot_account.test", "endpoints.*", "/vswitch"),
+				),
+			},
+		},
+	})
+}
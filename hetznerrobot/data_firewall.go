@@ -0,0 +1,175 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataFirewall() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFirewallRead,
+		Description: "Provides details about the firewall of a Hetzner Robot server",
+		Schema: map[string]*schema.Schema{
+			"server_ip": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"server_ip", "server_number"},
+				Description:  "Server IP address",
+			},
+			"server_number": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"server_ip", "server_number"},
+				Description:  "Server number",
+			},
+			// read-only / computed
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Firewall status (\"active\", \"disabled\" or \"in process\")",
+			},
+			"whitelist_hos": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether Hetzner services are whitelisted",
+			},
+			"filter_ipv6": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the rules are also applied to IPv6 traffic",
+			},
+			"port": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Switch port the firewall applies to (\"main\" or \"kvm\")",
+			},
+			"input_rules": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Input rules",
+				Elem:        dataFirewallRuleResource(),
+			},
+			"output_rules": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Output rules",
+				Elem:        dataFirewallRuleResource(),
+			},
+		},
+	}
+}
+
+func dataFirewallRuleResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"dst_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"dst_port": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"src_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"src_port": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"protocol": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"tcp_flags": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"action": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ip_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceFirewallRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	c, ok := meta.(HetznerRobotClient)
+	if !ok {
+		return diag.Errorf("Unable to cast meta to HetznerRobotClient")
+	}
+
+	// Robot accepts either the server IP or the server number
+	serverID, _ := d.Get("server_ip").(string)
+	if serverNumber, _ := d.Get("server_number").(int); serverID == "" && serverNumber != 0 {
+		serverID = strconv.Itoa(serverNumber)
+	}
+
+	firewall, err := c.getFirewall(ctx, serverID)
+	if err != nil {
+		return diag.Errorf("Unable to find Firewall for server %s:\n\t %q", serverID, err)
+	}
+
+	if err := d.Set("server_ip", firewall.IP); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("server_number", firewall.ServerNumber); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("status", firewall.Status); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("whitelist_hos", firewall.WhitelistHetznerServices); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("filter_ipv6", firewall.FilterIPv6); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("port", firewall.Port); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("input_rules", flattenFirewallRules(firewall.Rules.Input)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("output_rules", flattenFirewallRules(firewall.Rules.Output)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(firewall.IP)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	return diags
+}
+
+func flattenFirewallRules(firewallRules []HetznerRobotFirewallRule) []map[string]any {
+	rules := make([]map[string]any, 0, len(firewallRules))
+	for _, rule := range firewallRules {
+		rules = append(rules, map[string]any{
+			"name":       rule.Name,
+			"src_ip":     rule.SrcIP,
+			"src_port":   rule.SrcPort,
+			"dst_ip":     rule.DstIP,
+			"dst_port":   rule.DstPort,
+			"protocol":   rule.Protocol,
+			"tcp_flags":  rule.TCPFlags,
+			"action":     rule.Action,
+			"ip_version": rule.IPVersion,
+		})
+	}
+	return rules
+}
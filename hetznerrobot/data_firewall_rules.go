@@ -0,0 +1,319 @@
+package hetznerrobot
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataFirewallRules() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFirewallRulesRead,
+		Description: "Compresses high-level firewall intents into the minimum set of Hetzner Robot firewall rules",
+		Schema: map[string]*schema.Schema{
+			"intent": {
+				Type:        schema.TypeList,
+				Required:    true,
+				Description: "Services to allow or deny, in rule order",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Service name, used as rule name",
+						},
+						"ports": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Destination ports or port ranges, any port if empty",
+							Elem: &schema.Schema{
+								Type:             schema.TypeString,
+								ValidateDiagFunc: validateFirewallPort,
+							},
+						},
+						"sources": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Source addresses or CIDRs, any source if empty. Not supported with ip_version ipv6",
+							Elem: &schema.Schema{
+								Type:             schema.TypeString,
+								ValidateDiagFunc: validateFirewallIP,
+							},
+						},
+						"protocol": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      "Protocol, any protocol if empty",
+							ValidateDiagFunc: validateFirewallProtocol,
+						},
+						"action": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "accept",
+							Description: "Rule action",
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+								"accept",
+								"discard",
+							}, false)),
+						},
+						"ip_version": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "ipv4",
+							Description: "IP version of the traffic",
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+								"ipv4",
+								"ipv6",
+							}, false)),
+						},
+					},
+				},
+			},
+			"max_rules": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          firewallMaxInputRules,
+				Description:      "Maximum number of rules the intents may expand to",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(1, firewallMaxInputRules)),
+			},
+			// read-only / computed
+			"rules": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Rules for use in hetznerrobot_firewall dynamic rule blocks",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"src_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"dst_port": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"protocol": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"action": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip_version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFirewallRulesRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	maxRules, _ := d.Get("max_rules").(int)
+	intents, _ := d.Get("intent").([]any)
+
+	rules := make([]HetznerRobotFirewallRule, 0)
+	for _, intentMap := range intents {
+		intentProperties, ok := intentMap.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := intentProperties["name"].(string)
+		protocol, _ := intentProperties["protocol"].(string)
+		action, _ := intentProperties["action"].(string)
+		ipVersion, _ := intentProperties["ip_version"].(string)
+		ports := make([]string, 0)
+		if input, ok := intentProperties["ports"].([]any); ok {
+			for _, port := range input {
+				if portStr, ok := port.(string); ok {
+					ports = append(ports, portStr)
+				}
+			}
+		}
+		sources := make([]string, 0)
+		if input, ok := intentProperties["sources"].([]any); ok {
+			for _, source := range input {
+				if sourceStr, ok := source.(string); ok {
+					sources = append(sources, sourceStr)
+				}
+			}
+		}
+
+		intentRules, err := expandFirewallIntent(name, protocol, action, ipVersion, ports, sources)
+		if err != nil {
+			return diag.Errorf("Unable to expand firewall intent %q:\n\t %q", name, err)
+		}
+		rules = append(rules, intentRules...)
+	}
+
+	if len(rules) > maxRules {
+		return diag.Errorf("Firewall intents expand to %d rules after merging, but at most %d are allowed", len(rules), maxRules)
+	}
+
+	result := make([]map[string]any, 0, len(rules))
+	ids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, map[string]any{
+			"name":       rule.Name,
+			"src_ip":     rule.SrcIP,
+			"dst_port":   rule.DstPort,
+			"protocol":   rule.Protocol,
+			"action":     rule.Action,
+			"ip_version": rule.IPVersion,
+		})
+		ids = append(ids, strings.Join([]string{rule.Name, rule.SrcIP, rule.DstPort, rule.Protocol, rule.Action, rule.IPVersion}, ","))
+	}
+
+	if err := d.Set("rules", result); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.Itoa(schema.HashString(strings.Join(ids, ";"))))
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	return diags
+}
+
+// expandFirewallIntent returns one rule per merged source and port range.
+func expandFirewallIntent(name string, protocol string, action string, ipVersion string, ports []string, sources []string) ([]HetznerRobotFirewallRule, error) {
+	// Robot rejects addresses on IPv6 rules instead of filtering by them
+	if ipVersion == "ipv6" && len(sources) > 0 {
+		return nil, fmt.Errorf("sources are not supported with ip_version ipv6")
+	}
+
+	prefixes := make([]netip.Prefix, 0, len(sources))
+	for _, source := range sources {
+		prefix, err := parseFirewallPrefix(source)
+		if err != nil {
+			return nil, err
+		}
+		if prefix.Addr().Is4() != (ipVersion != "ipv6") {
+			return nil, fmt.Errorf("source %s does not match ip_version %s", source, ipVersion)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	srcIPs := []string{""}
+	if len(prefixes) > 0 {
+		srcIPs = make([]string, 0, len(prefixes))
+		for _, prefix := range mergeFirewallPrefixes(prefixes) {
+			srcIPs = append(srcIPs, prefix.String())
+		}
+	}
+
+	dstPorts := []string{""}
+	if len(ports) > 0 {
+		merged, err := mergeFirewallPorts(ports)
+		if err != nil {
+			return nil, err
+		}
+		dstPorts = merged
+	}
+
+	rules := make([]HetznerRobotFirewallRule, 0, len(srcIPs)*len(dstPorts))
+	for _, srcIP := range srcIPs {
+		for _, dstPort := range dstPorts {
+			rules = append(rules, HetznerRobotFirewallRule{
+				Name:      name,
+				SrcIP:     srcIP,
+				DstPort:   dstPort,
+				Protocol:  protocol,
+				Action:    action,
+				IPVersion: ipVersion,
+			})
+		}
+	}
+	return rules, nil
+}
+
+// mergeFirewallPrefixes drops prefixes covered by others and joins sibling
+// prefixes into their parent until no further merge is possible. The result
+// matches exactly the same addresses as the input.
+func mergeFirewallPrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	sorted := make([]netip.Prefix, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		sorted = append(sorted, prefix.Masked())
+	}
+	slices.SortFunc(sorted, func(a, b netip.Prefix) int {
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Bits(), b.Bits())
+	})
+
+	merged := make([]netip.Prefix, 0, len(sorted))
+	for _, prefix := range sorted {
+		// sorted by address with wider prefixes first, so anything overlapping
+		// the previous prefix is covered by it
+		if len(merged) > 0 && merged[len(merged)-1].Overlaps(prefix) {
+			continue
+		}
+		merged = append(merged, prefix)
+		for len(merged) > 1 {
+			last, prev := merged[len(merged)-1], merged[len(merged)-2]
+			if last.Bits() != prev.Bits() || last.Bits() == 0 {
+				break
+			}
+			parent := netip.PrefixFrom(prev.Addr(), prev.Bits()-1).Masked()
+			if parent.Addr() != prev.Addr() || !parent.Contains(last.Addr()) {
+				break
+			}
+			merged = append(merged[:len(merged)-2], parent)
+		}
+	}
+	return merged
+}
+
+// mergeFirewallPorts joins overlapping and adjacent ports and port ranges.
+func mergeFirewallPorts(ports []string) ([]string, error) {
+	type portRange struct{ start, end int }
+
+	ranges := make([]portRange, 0, len(ports))
+	for _, port := range ports {
+		if err := checkFirewallPort(port); err != nil {
+			return nil, err
+		}
+		from, to, isRange := strings.Cut(port, "-")
+		start, _ := strconv.Atoi(from)
+		end := start
+		if isRange {
+			end, _ = strconv.Atoi(to)
+		}
+		ranges = append(ranges, portRange{start, end})
+	}
+	slices.SortFunc(ranges, func(a, b portRange) int { return cmp.Compare(a.start, b.start) })
+
+	merged := make([]portRange, 0, len(ranges))
+	for _, r := range ranges {
+		if len(merged) > 0 && r.start <= merged[len(merged)-1].end+1 {
+			merged[len(merged)-1].end = max(merged[len(merged)-1].end, r.end)
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	result := make([]string, 0, len(merged))
+	for _, r := range merged {
+		if r.start == r.end {
+			result = append(result, strconv.Itoa(r.start))
+		} else {
+			result = append(result, fmt.Sprintf("%d-%d", r.start, r.end))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,265 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestMergeFirewallPrefixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefixes []string
+		expected []string
+	}{
+		{
+			name:     "single",
+			prefixes: []string{"10.0.0.0/24"},
+			expected: []string{"10.0.0.0/24"},
+		},
+		{
+			name:     "duplicates",
+			prefixes: []string{"10.0.0.0/24", "10.0.0.0/24"},
+			expected: []string{"10.0.0.0/24"},
+		},
+		{
+			name:     "covered",
+			prefixes: []string{"10.1.0.0/16", "10.0.0.0/8", "10.2.3.4/32"},
+			expected: []string{"10.0.0.0/8"},
+		},
+		{
+			name:     "siblings",
+			prefixes: []string{"10.0.1.0/24", "10.0.0.0/24"},
+			expected: []string{"10.0.0.0/23"},
+		},
+		{
+			name:     "cascading siblings",
+			prefixes: []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/23"},
+			expected: []string{"10.0.0.0/22"},
+		},
+		{
+			name:     "adjacent but not siblings",
+			prefixes: []string{"10.0.1.0/24", "10.0.2.0/24"},
+			expected: []string{"10.0.1.0/24", "10.0.2.0/24"},
+		},
+		{
+			name:     "single addresses",
+			prefixes: []string{"192.168.1.11/32", "192.168.1.10/32", "192.168.1.12/32"},
+			expected: []string{"192.168.1.10/31", "192.168.1.12/32"},
+		},
+		{
+			name:     "whole internet",
+			prefixes: []string{"0.0.0.0/1", "128.0.0.0/1"},
+			expected: []string{"0.0.0.0/0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefixes := make([]netip.Prefix, 0, len(tt.prefixes))
+			for _, p := range tt.prefixes {
+				prefixes = append(prefixes, netip.MustParsePrefix(p))
+			}
+
+			result := make([]string, 0)
+			for _, p := range mergeFirewallPrefixes(prefixes) {
+				result = append(result, p.String())
+			}
+
+			if !slices.Equal(result, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMergeFirewallPorts(t *testing.T) {
+	tests := []struct {
+		name      string
+		ports     []string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "single",
+			ports:    []string{"22"},
+			expected: []string{"22"},
+		},
+		{
+			name:     "adjacent",
+			ports:    []string{"81", "80", "82-90"},
+			expected: []string{"80-90"},
+		},
+		{
+			name:     "overlapping",
+			ports:    []string{"1000-2000", "1500-2500", "1800"},
+			expected: []string{"1000-2500"},
+		},
+		{
+			name:     "separate",
+			ports:    []string{"443", "80", "22"},
+			expected: []string{"22", "80", "443"},
+		},
+		{
+			name:      "invalid",
+			ports:     []string{"22-"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := mergeFirewallPorts(tt.ports)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(result, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestExpandFirewallIntent(t *testing.T) {
+	rules, err := expandFirewallIntent("SSH", "tcp", "accept", "ipv4",
+		[]string{"22", "2222"},
+		[]string{"10.0.0.0/24", "10.0.1.0/24", "172.16.0.1"},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []HetznerRobotFirewallRule{
+		{Name: "SSH", SrcIP: "10.0.0.0/23", DstPort: "22", Protocol: "tcp", Action: "accept", IPVersion: "ipv4"},
+		{Name: "SSH", SrcIP: "10.0.0.0/23", DstPort: "2222", Protocol: "tcp", Action: "accept", IPVersion: "ipv4"},
+		{Name: "SSH", SrcIP: "172.16.0.1/32", DstPort: "22", Protocol: "tcp", Action: "accept", IPVersion: "ipv4"},
+		{Name: "SSH", SrcIP: "172.16.0.1/32", DstPort: "2222", Protocol: "tcp", Action: "accept", IPVersion: "ipv4"},
+	}
+	if !slices.Equal(rules, expected) {
+		t.Fatalf("Expected %v, got %v", expected, rules)
+	}
+
+	rules, err = expandFirewallIntent("Any", "", "accept", "ipv4", nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rules) != 1 || rules[0].SrcIP != "" || rules[0].DstPort != "" {
+		t.Fatalf("Expected a single rule without source and port, got %v", rules)
+	}
+
+	if _, err := expandFirewallIntent("Mixed", "tcp", "accept", "ipv4", nil, []string{"2001:db8::/32"}); err == nil {
+		t.Fatal("Expected error for IPv6 source in IPv4 intent")
+	}
+
+	// an IPv6 allowlist would otherwise become "accept from any IPv6"
+	if _, err := expandFirewallIntent("Mixed", "tcp", "accept", "ipv6", nil, []string{"2001:db8::/32"}); err == nil {
+		t.Fatal("Expected error for sources in IPv6 intent")
+	}
+	rules, err = expandFirewallIntent("SSH", "tcp", "accept", "ipv6", []string{"22"}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rules) != 1 || rules[0].SrcIP != "" || rules[0].IPVersion != "ipv6" {
+		t.Fatalf("Expected a single IPv6 rule without source, got %v", rules)
+	}
+}
+
+func TestDataSourceFirewallRulesRead(t *testing.T) {
+	intent := func(name string, sources ...any) map[string]any {
+		return map[string]any{
+			"name":     name,
+			"ports":    []any{"443"},
+			"sources":  sources,
+			"protocol": "tcp",
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, dataFirewallRules().Schema, map[string]any{
+		"intent": []any{
+			intent("HTTPS", "10.0.0.0/25", "10.0.0.128/25", "10.0.1.0/24"),
+			intent("Admin", "192.168.0.1"),
+		},
+	})
+	if diags := dataSourceFirewallRulesRead(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	rules, _ := d.Get("rules").([]any)
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d: %v", len(rules), rules)
+	}
+	first, _ := rules[0].(map[string]any)
+	if first["src_ip"] != "10.0.0.0/23" || first["dst_port"] != "443" || first["action"] != "accept" || first["ip_version"] != "ipv4" {
+		t.Fatalf("Unexpected first rule: %v", first)
+	}
+
+	d = schema.TestResourceDataRaw(t, dataFirewallRules().Schema, map[string]any{
+		"intent":    []any{intent("HTTPS", "10.0.0.1", "10.0.0.3", "10.0.0.5")},
+		"max_rules": 2,
+	})
+	diags := dataSourceFirewallRulesRead(context.Background(), d, nil)
+	if !diags.HasError() {
+		t.Fatal("Expected error when exceeding max_rules")
+	}
+	if !strings.Contains(diags[0].Summary, "expand to 3 rules") {
+		t.Fatalf("Unexpected error: %v", diags[0].Summary)
+	}
+}
+
+func TestAccDataSourceFirewallRules(t *testing.T) {
+	mock, providerConfig := testAccRobotMock(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+data "hetznerrobot_firewall_rules" "test" {
+  intent {
+    name     = "HTTPS"
+    ports    = ["443"]
+    sources  = ["10.0.0.0/25", "10.0.0.128/25"]
+    protocol = "tcp"
+  }
+}
+
+resource "hetznerrobot_firewall" "test" {
+  server_ip     = %q
+  active        = true
+  whitelist_hos = true
+
+  dynamic "rule" {
+    for_each = data.hetznerrobot_firewall_rules.test.rules
+    content {
+      name       = rule.value.name
+      src_ip     = rule.value.src_ip
+      dst_port   = rule.value.dst_port
+      protocol   = rule.value.protocol
+      action     = rule.value.action
+      ip_version = rule.value.ip_version
+    }
+  }
+}
+`, testAccServerIP),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetznerrobot_firewall_rules.test", "rules.#", "1"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_firewall_rules.test", "rules.0.src_ip", "10.0.0.0/24"),
+					resource.TestCheckResourceAttr("hetznerrobot_firewall.test", "rule.0.src_ip", "10.0.0.0/24"),
+					testAccCheckFirewall(mock, "active", "HTTPS"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,126 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/robotmock"
+)
+
+func TestDataSourceFirewallRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/firewall/1.2.3.4", "/firewall/321":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{
+				"firewall": {
+					"server_ip": "1.2.3.4",
+					"server_number": 321,
+					"status": "active",
+					"whitelist_hos": true,
+					"port": "main",
+					"rules": {
+						"input": [
+							{"name": "SSH", "src_ip": "10.0.0.0/8", "dst_port": "22", "protocol": "tcp", "action": "accept", "ip_version": "ipv4"}
+						],
+						"output": [
+							{"name": "Allow all", "action": "accept"}
+						]
+					}
+				}
+			}`))
+		default:
+			http.Error(w, "Not Found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	tests := []struct {
+		name   string
+		config map[string]any
+	}{
+		{
+			name:   "by server ip",
+			config: map[string]any{"server_ip": "1.2.3.4"},
+		},
+		{
+			name:   "by server number",
+			config: map[string]any{"server_number": 321},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, dataFirewall().Schema, tt.config)
+			if diags := dataSourceFirewallRead(context.Background(), d, client); diags.HasError() {
+				t.Fatalf("Unexpected error: %v", diags)
+			}
+
+			if d.Id() != "1.2.3.4" {
+				t.Fatalf("Expected ID 1.2.3.4, got %s", d.Id())
+			}
+			if d.Get("server_number").(int) != 321 {
+				t.Fatalf("Expected server number 321, got %v", d.Get("server_number"))
+			}
+			if d.Get("status").(string) != "active" {
+				t.Fatalf("Expected status active, got %v", d.Get("status"))
+			}
+			if d.Get("port").(string) != "main" {
+				t.Fatalf("Expected port main, got %v", d.Get("port"))
+			}
+			if !d.Get("whitelist_hos").(bool) {
+				t.Fatal("Expected whitelist_hos to be true")
+			}
+			if d.Get("input_rules.#").(int) != 1 || d.Get("input_rules.0.dst_port").(string) != "22" {
+				t.Fatalf("Unexpected input rules: %v", d.Get("input_rules"))
+			}
+			if d.Get("output_rules.#").(int) != 1 || d.Get("output_rules.0.name").(string) != "Allow all" {
+				t.Fatalf("Unexpected output rules: %v", d.Get("output_rules"))
+			}
+		})
+	}
+}
+
+func TestAccDataSourceFirewall(t *testing.T) {
+	mock, providerConfig := testAccRobotMock(t)
+	mock.SetFirewall(testAccServerNumber, robotmock.Firewall{
+		Status:       "active",
+		WhitelistHOS: true,
+		Port:         "main",
+		Input: []robotmock.FirewallRule{
+			{Name: "ssh", IPVersion: "ipv4", DstPort: "22", Protocol: "tcp", Action: "accept"},
+		},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+data "hetznerrobot_firewall" "by_ip" {
+  server_ip = %q
+}
+
+data "hetznerrobot_firewall" "by_number" {
+  server_number = %d
+}
+`, testAccServerIP, testAccServerNumber),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetznerrobot_firewall.by_ip", "status", "active"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_firewall.by_ip", "server_number", strconv.Itoa(testAccServerNumber)),
+					resource.TestCheckResourceAttr("data.hetznerrobot_firewall.by_ip", "input_rules.#", "1"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_firewall.by_ip", "input_rules.0.name", "ssh"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_firewall.by_number", "server_ip", testAccServerIP),
+				),
+			},
+		},
+	})
+}
@@ -162,19 +162,19 @@ func dataSourceServerRead(ctx context.Context, d *schema.ResourceData, meta any)
 	if err := d.Set("product", server.Product); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("server_ip_addresses", server.IPs); err != nil {
+	if err := d.Set("ip_addresses", server.IPs); err != nil {
 		return diag.FromErr(err)
 	}
 	if err := d.Set("server_ip", server.ServerIP); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("server_ip_v6_net", server.ServerIPv6); err != nil {
+	if err := d.Set("server_ipv6", server.ServerIPv6); err != nil {
 		return diag.FromErr(err)
 	}
 	if err := d.Set("server_name", server.ServerName); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("server_subnets", server.Subnets); err != nil {
+	if err := d.Set("server_subnets", flattenServerSubnets(server.Subnets)); err != nil {
 		return diag.FromErr(err)
 	}
 	if err := d.Set("status", server.Status); err != nil {
@@ -190,3 +190,14 @@ func dataSourceServerRead(ctx context.Context, d *schema.ResourceData, meta any)
 
 	return diags
 }
+
+func flattenServerSubnets(subnets []HetznerRobotServerSubnet) []map[string]any {
+	result := make([]map[string]any, 0, len(subnets))
+	for _, subnet := range subnets {
+		result = append(result, map[string]any{
+			"ip":   subnet.IP,
+			"mask": subnet.Mask,
+		})
+	}
+	return result
+}
@@ -0,0 +1,224 @@
+package hetznerrobot
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/crc32"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type serverMarketProductsDataSource struct {
+	client HetznerRobotClient
+}
+
+var _ datasource.DataSourceWithConfigure = (*serverMarketProductsDataSource)(nil)
+
+func newServerMarketProductsDataSource() datasource.DataSource {
+	return &serverMarketProductsDataSource{}
+}
+
+type serverMarketProductsModel struct {
+	ID            types.String               `tfsdk:"id"`
+	CPU           types.String               `tfsdk:"cpu"`
+	MinMemorySize types.Int64                `tfsdk:"min_memory_size"`
+	MinHDDSize    types.Int64                `tfsdk:"min_hdd_size"`
+	MinHDDCount   types.Int64                `tfsdk:"min_hdd_count"`
+	Datacenter    types.String               `tfsdk:"datacenter"`
+	MaxPrice      types.Float64              `tfsdk:"max_price"`
+	FixedPrice    types.Bool                 `tfsdk:"fixed_price"`
+	Products      []serverMarketProductModel `tfsdk:"products"`
+}
+
+type serverMarketProductModel struct {
+	ID             int64    `tfsdk:"id"`
+	Name           string   `tfsdk:"name"`
+	Description    []string `tfsdk:"description"`
+	Traffic        string   `tfsdk:"traffic"`
+	Dist           []string `tfsdk:"dist"`
+	CPU            string   `tfsdk:"cpu"`
+	CPUBenchmark   int64    `tfsdk:"cpu_benchmark"`
+	MemorySize     int64    `tfsdk:"memory_size"`
+	HDDSize        int64    `tfsdk:"hdd_size"`
+	HDDText        string   `tfsdk:"hdd_text"`
+	HDDCount       int64    `tfsdk:"hdd_count"`
+	Datacenter     string   `tfsdk:"datacenter"`
+	NetworkSpeed   string   `tfsdk:"network_speed"`
+	Price          float64  `tfsdk:"price"`
+	PriceSetup     float64  `tfsdk:"price_setup"`
+	FixedPrice     bool     `tfsdk:"fixed_price"`
+	NextReduce     int64    `tfsdk:"next_reduce"`
+	NextReduceDate string   `tfsdk:"next_reduce_date"`
+}
+
+func (d *serverMarketProductsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_market_products"
+}
+
+func (d *serverMarketProductsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the servers offered on the Hetzner Robot server market (auction) that match all given filters, cheapest first",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hash of the IDs of the matching products",
+			},
+			"cpu": schema.StringAttribute{
+				Optional:    true,
+				Description: "Part of the CPU model, matched case-insensitively, e.g. \"Ryzen\"",
+			},
+			"min_memory_size": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum memory in GB",
+			},
+			"min_hdd_size": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum size of a single drive in GB",
+			},
+			"min_hdd_count": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum number of drives",
+			},
+			"datacenter": schema.StringAttribute{
+				Optional:    true,
+				Description: "Datacenter or location prefix, e.g. \"FSN1\" or \"FSN1-DC14\"",
+			},
+			"max_price": schema.Float64Attribute{
+				Optional:    true,
+				Description: "Maximum monthly price in EUR, excluding VAT",
+			},
+			"fixed_price": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only products with (true) or without (false) a fixed price. Products without one get cheaper over time",
+			},
+			"products": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Matching products, sorted by price and then by ID",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id":               schema.Int64Attribute{Computed: true, Description: "Product ID, used to order the server"},
+						"name":             schema.StringAttribute{Computed: true, Description: "Product name"},
+						"description":      schema.ListAttribute{Computed: true, ElementType: types.StringType, Description: "Description lines"},
+						"traffic":          schema.StringAttribute{Computed: true, Description: "Included traffic"},
+						"dist":             schema.ListAttribute{Computed: true, ElementType: types.StringType, Description: "Available distributions"},
+						"cpu":              schema.StringAttribute{Computed: true, Description: "CPU model"},
+						"cpu_benchmark":    schema.Int64Attribute{Computed: true, Description: "CPU benchmark score"},
+						"memory_size":      schema.Int64Attribute{Computed: true, Description: "Memory in GB"},
+						"hdd_size":         schema.Int64Attribute{Computed: true, Description: "Size of a single drive in GB"},
+						"hdd_text":         schema.StringAttribute{Computed: true, Description: "Drive description"},
+						"hdd_count":        schema.Int64Attribute{Computed: true, Description: "Number of drives"},
+						"datacenter":       schema.StringAttribute{Computed: true, Description: "Datacenter"},
+						"network_speed":    schema.StringAttribute{Computed: true, Description: "Network speed"},
+						"price":            schema.Float64Attribute{Computed: true, Description: "Monthly price in EUR, excluding VAT"},
+						"price_setup":      schema.Float64Attribute{Computed: true, Description: "Setup price in EUR, excluding VAT"},
+						"fixed_price":      schema.BoolAttribute{Computed: true, Description: "Whether the price is fixed"},
+						"next_reduce":      schema.Int64Attribute{Computed: true, Description: "Seconds until the next price reduction"},
+						"next_reduce_date": schema.StringAttribute{Computed: true, Description: "Time of the next price reduction"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *serverMarketProductsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// ProviderData is nil until the provider is configured
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(HetznerRobotClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", "Unable to cast provider data to HetznerRobotClient")
+		return
+	}
+	d.client = client
+}
+
+func (d *serverMarketProductsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data serverMarketProductsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	products, err := d.client.getServerMarketProducts(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to list server market products", err.Error())
+		return
+	}
+
+	data.Products, err = filterServerMarketProducts(products, data)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to list server market products", err.Error())
+		return
+	}
+	ids := make([]string, 0, len(data.Products))
+	for _, product := range data.Products {
+		ids = append(ids, strconv.FormatInt(product.ID, 10))
+	}
+	data.ID = types.StringValue(strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(strings.Join(ids, ",")))), 10))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// filterServerMarketProducts returns the products matching all filters set in
+// filter, cheapest first.
+func filterServerMarketProducts(products []HetznerRobotServerMarketProduct, filter serverMarketProductsModel) ([]serverMarketProductModel, error) {
+	cpu := strings.ToLower(filter.CPU.ValueString())
+	datacenter := strings.ToUpper(filter.Datacenter.ValueString())
+
+	result := make([]serverMarketProductModel, 0, len(products))
+	for _, product := range products {
+		price, err := product.Price.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %q of product %d: %w", product.Price, product.ID, err)
+		}
+		priceSetup, err := product.PriceSetup.Float64()
+		if err != nil && product.PriceSetup != "" {
+			return nil, fmt.Errorf("invalid setup price %q of product %d: %w", product.PriceSetup, product.ID, err)
+		}
+
+		switch {
+		case !strings.Contains(strings.ToLower(product.CPU), cpu),
+			!strings.HasPrefix(strings.ToUpper(product.Datacenter), datacenter),
+			int64(product.MemorySize) < filter.MinMemorySize.ValueInt64(),
+			int64(product.HDDSize) < filter.MinHDDSize.ValueInt64(),
+			int64(product.HDDCount) < filter.MinHDDCount.ValueInt64(),
+			!filter.MaxPrice.IsNull() && price > filter.MaxPrice.ValueFloat64(),
+			!filter.FixedPrice.IsNull() && product.FixedPrice != filter.FixedPrice.ValueBool():
+			continue
+		}
+
+		result = append(result, serverMarketProductModel{
+			ID:             int64(product.ID),
+			Name:           product.Name,
+			Description:    product.Description,
+			Traffic:        product.Traffic,
+			Dist:           product.Dist,
+			CPU:            product.CPU,
+			CPUBenchmark:   int64(product.CPUBenchmark),
+			MemorySize:     int64(product.MemorySize),
+			HDDSize:        int64(product.HDDSize),
+			HDDText:        product.HDDText,
+			HDDCount:       int64(product.HDDCount),
+			Datacenter:     product.Datacenter,
+			NetworkSpeed:   product.NetworkSpeed,
+			Price:          price,
+			PriceSetup:     priceSetup,
+			FixedPrice:     product.FixedPrice,
+			NextReduce:     int64(product.NextReduce),
+			NextReduceDate: product.NextReduceDate,
+		})
+	}
+
+	slices.SortStableFunc(result, func(a, b serverMarketProductModel) int {
+		return cmp.Or(cmp.Compare(a.Price, b.Price), cmp.Compare(a.ID, b.ID))
+	})
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package hetznerrobot
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/robotmock"
+)
+
+var testServerMarketProducts = []HetznerRobotServerMarketProduct{
+	{ID: 1, CPU: "Intel Core i7-6700", MemorySize: 64, HDDSize: 512, HDDCount: 2, Datacenter: "FSN1-DC14", Price: "39.0000"},
+	{ID: 2, CPU: "AMD Ryzen 7 3700X", MemorySize: 64, HDDSize: 1024, HDDCount: 2, Datacenter: "HEL1-DC2", Price: "45.0000", FixedPrice: true},
+	{ID: 3, CPU: "Intel Xeon E3-1275V6", MemorySize: 32, HDDSize: 4096, HDDCount: 4, Datacenter: "FSN1-DC8", Price: "35.5000"},
+	{ID: 4, CPU: "Intel Core i7-6700", MemorySize: 64, HDDSize: 512, HDDCount: 2, Datacenter: "NBG1-DC3", Price: "39.0000"},
+}
+
+func TestFilterServerMarketProducts(t *testing.T) {
+	unfiltered := serverMarketProductsModel{
+		CPU:           types.StringNull(),
+		MinMemorySize: types.Int64Null(),
+		MinHDDSize:    types.Int64Null(),
+		MinHDDCount:   types.Int64Null(),
+		Datacenter:    types.StringNull(),
+		MaxPrice:      types.Float64Null(),
+		FixedPrice:    types.BoolNull(),
+	}
+
+	tests := []struct {
+		name     string
+		filter   func(filter *serverMarketProductsModel)
+		expected []int64
+	}{
+		{name: "no filter sorts by price and ID", filter: func(*serverMarketProductsModel) {}, expected: []int64{3, 1, 4, 2}},
+		{name: "cpu", filter: func(f *serverMarketProductsModel) { f.CPU = types.StringValue("i7") }, expected: []int64{1, 4}},
+		{name: "memory", filter: func(f *serverMarketProductsModel) { f.MinMemorySize = types.Int64Value(64) }, expected: []int64{1, 4, 2}},
+		{name: "hdd size", filter: func(f *serverMarketProductsModel) { f.MinHDDSize = types.Int64Value(1024) }, expected: []int64{3, 2}},
+		{name: "hdd count", filter: func(f *serverMarketProductsModel) { f.MinHDDCount = types.Int64Value(3) }, expected: []int64{3}},
+		{name: "location", filter: func(f *serverMarketProductsModel) { f.Datacenter = types.StringValue("fsn1") }, expected: []int64{3, 1}},
+		{name: "datacenter", filter: func(f *serverMarketProductsModel) { f.Datacenter = types.StringValue("FSN1-DC14") }, expected: []int64{1}},
+		{name: "max price", filter: func(f *serverMarketProductsModel) { f.MaxPrice = types.Float64Value(39) }, expected: []int64{3, 1, 4}},
+		{name: "fixed price", filter: func(f *serverMarketProductsModel) { f.FixedPrice = types.BoolValue(true) }, expected: []int64{2}},
+		{name: "no fixed price", filter: func(f *serverMarketProductsModel) { f.FixedPrice = types.BoolValue(false) }, expected: []int64{3, 1, 4}},
+		{name: "no match", filter: func(f *serverMarketProductsModel) { f.MaxPrice = types.Float64Value(10) }, expected: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := unfiltered
+			tt.filter(&filter)
+			products, err := filterServerMarketProducts(testServerMarketProducts, filter)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			ids := make([]int64, 0, len(products))
+			for _, product := range products {
+				ids = append(ids, product.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.expected) {
+				t.Fatalf("Expected products %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+
+	invalid := []HetznerRobotServerMarketProduct{{ID: 5, Price: json.Number("n/a")}}
+	if _, err := filterServerMarketProducts(invalid, unfiltered); err == nil {
+		t.Fatal("Expected an error for an invalid price")
+	}
+}
+
+func TestAccDataSourceServerMarketProducts(t *testing.T) {
+	mock, providerConfig := testAccRobotMock(t)
+	mock.AddMarketProduct(robotmock.MarketProduct{ID: 10, CPU: "AMD Ryzen 7 3700X", MemorySize: 64, HDDSize: 1024, HDDCount: 2, Datacenter: "HEL1-DC2", Price: "45.0000"})
+	mock.AddMarketProduct(robotmock.MarketProduct{ID: 11, CPU: "AMD Ryzen 5 3600", MemorySize: 64, HDDSize: 512, HDDCount: 2, Datacenter: "FSN1-DC14", Price: "37.0000", PriceSetup: "10.0000"})
+	mock.AddMarketProduct(robotmock.MarketProduct{ID: 12, CPU: "Intel Core i7-6700", MemorySize: 32, HDDSize: 512, HDDCount: 2, Datacenter: "FSN1-DC8", Price: "29.0000"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "hetznerrobot_server_market_products" "test" {
+  cpu             = "ryzen"
+  min_memory_size = 64
+  max_price       = 50
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetznerrobot_server_market_products.test", "products.#", "2"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_server_market_products.test", "products.0.id", "11"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_server_market_products.test", "products.0.price", "37"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_server_market_products.test", "products.0.price_setup", "10"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_server_market_products.test", "products.0.datacenter", "FSN1-DC14"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_server_market_products.test", "products.1.id", "10"),
+				),
+			},
+		},
+	})
+}
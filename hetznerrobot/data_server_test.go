@@ -0,0 +1,68 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/robotmock"
+)
+
+func TestAccDataSourceServer(t *testing.T) {
+	_, providerConfig := testAccRobotMock(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+data "hetznerrobot_server" "test" {
+  server_number = %d
+}
+`, testAccServerNumber),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetznerrobot_server.test", "id", strconv.Itoa(testAccServerNumber)),
+					resource.TestCheckResourceAttr("data.hetznerrobot_server.test", "server_ip", testAccServerIP),
+					resource.TestCheckResourceAttrSet("data.hetznerrobot_server.test", "server_ipv6"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_server.test", "ip_addresses.#", "1"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_server.test", "ip_addresses.0", testAccServerIP),
+					resource.TestCheckResourceAttr("data.hetznerrobot_server.test", "server_subnets.#", "1"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_server.test", "status", "ready"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceServerRead(t *testing.T) {
+	mock := robotmock.New(robotmock.Config{Username: "user", Password: "pass"})
+	mock.AddServer(robotmock.Server{Number: 1001, IP: "198.51.100.1", IPv6Net: "2001:db8:1001::"})
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	d := schema.TestResourceDataRaw(t, dataServer().Schema, map[string]any{
+		"server_number": 1001,
+	})
+	if diags := dataSourceServerRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	if got := d.Get("ip_addresses").([]any); len(got) != 1 || got[0] != "198.51.100.1" {
+		t.Fatalf("Expected ip_addresses [198.51.100.1], got %v", got)
+	}
+	if got := d.Get("server_ipv6"); got != "2001:db8:1001::" {
+		t.Fatalf("Expected server_ipv6 2001:db8:1001::, got %v", got)
+	}
+	subnets := d.Get("server_subnets").([]any)
+	if len(subnets) != 1 {
+		t.Fatalf("Expected one subnet, got %v", subnets)
+	}
+	if subnet := subnets[0].(map[string]any); subnet["ip"] != "2001:db8:1001::" || subnet["mask"] != "64" {
+		t.Fatalf("Unexpected subnet %v", subnet)
+	}
+}
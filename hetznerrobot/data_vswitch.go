@@ -2,6 +2,7 @@ package hetznerrobot
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -10,15 +11,25 @@ import (
 func dataVSwitch() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceVSwitchRead,
-		Description: "Provides details about a Hetzner Robot vSwitch",
+		Description: "Provides details about a Hetzner Robot vSwitch, selected by ID or by name and/or VLAN",
 		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"name", "vlan"},
+				AtLeastOneOf:  []string{"id", "name", "vlan"},
+				Description:   "vSwitch ID",
+			},
 			"name": {
 				Type:        schema.TypeString,
+				Optional:    true,
 				Computed:    true,
 				Description: "vSwitch name",
 			},
 			"vlan": {
 				Type:        schema.TypeInt,
+				Optional:    true,
 				Computed:    true,
 				Description: "VLAN ID",
 			},
@@ -31,72 +42,84 @@ func dataVSwitch() *schema.Resource {
 				Type:        schema.TypeList,
 				Computed:    true,
 				Description: "Attached server list",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"server_ip": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"server_ipv6_net": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"server_number": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-						"status": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
+				Elem:        dataVSwitchServerResource(),
 			},
 			"subnets": {
 				Type:        schema.TypeList,
 				Computed:    true,
 				Description: "Attached subnet list",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"ip": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"mask": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-						"gateway": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
+				Elem:        dataVSwitchSubnetResource(),
 			},
 			"cloud_networks": {
 				Type:        schema.TypeList,
 				Computed:    true,
 				Description: "Attached cloud network list",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-						"ip": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"mask": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-						"gateway": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
+				Elem:        dataVSwitchCloudNetworkResource(),
+			},
+		},
+	}
+}
+
+func dataVSwitchServerResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"server_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"server_ipv6_net": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"server_number": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataVSwitchSubnetResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"mask": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"gateway": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataVSwitchCloudNetworkResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"mask": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"gateway": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 		},
 	}
@@ -108,7 +131,26 @@ func dataSourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta any
 		return diag.Errorf("Unable to cast meta to HetznerRobotClient")
 	}
 
-	vSwitchID := d.Id()
+	vSwitchID, _ := d.Get("id").(string)
+	if vSwitchID == "" {
+		name, _ := d.Get("name").(string)
+		vlan, _ := d.Get("vlan").(int)
+
+		vSwitches, err := c.getVSwitches(ctx)
+		if err != nil {
+			return diag.Errorf("Unable to list VSwitches:\n\t %q", err)
+		}
+		matches := filterVSwitches(vSwitches, name, vlan)
+		switch len(matches) {
+		case 0:
+			return diag.Errorf("No VSwitch found with name %q and VLAN %d", name, vlan)
+		case 1:
+			vSwitchID = strconv.Itoa(matches[0].ID)
+		default:
+			return diag.Errorf("Found %d VSwitches with name %q and VLAN %d, add more arguments to select a single one", len(matches), name, vlan)
+		}
+	}
+
 	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
 	if err != nil {
 		return diag.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err)
@@ -123,13 +165,13 @@ func dataSourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta any
 	if err := d.Set("is_canceled", vSwitch.Canceled); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("servers", vSwitch.Server); err != nil {
+	if err := d.Set("servers", flattenVSwitchServers(vSwitch.Server, nil)); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("subnets", vSwitch.Subnet); err != nil {
+	if err := d.Set("subnets", flattenVSwitchSubnets(vSwitch.Subnet)); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("cloud_networks", vSwitch.CloudNetwork); err != nil {
+	if err := d.Set("cloud_networks", flattenVSwitchCloudNetworks(vSwitch.CloudNetwork)); err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(vSwitchID)
@@ -139,3 +181,19 @@ func dataSourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta any
 
 	return diags
 }
+
+// filterVSwitches returns the vSwitches matching name and vlan, where an
+// empty name or a zero vlan matches any vSwitch.
+func filterVSwitches(vSwitches []HetznerRobotVSwitch, name string, vlan int) []HetznerRobotVSwitch {
+	result := make([]HetznerRobotVSwitch, 0, 1)
+	for _, vSwitch := range vSwitches {
+		if name != "" && vSwitch.Name != name {
+			continue
+		}
+		if vlan != 0 && vSwitch.Vlan != vlan {
+			continue
+		}
+		result = append(result, vSwitch)
+	}
+	return result
+}
@@ -0,0 +1,160 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func newVSwitchesTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/vswitch":
+			w.Write([]byte(`[
+				{"id": 1, "name": "prod", "vlan": 4000, "canceled": false},
+				{"id": 2, "name": "test", "vlan": 4001, "canceled": false},
+				{"id": 3, "name": "test", "vlan": 4002, "canceled": true}
+			]`))
+		case "/vswitch/1":
+			w.Write([]byte(`{"id": 1, "name": "prod", "vlan": 4000, "canceled": false,
+				"server": [{"server_number": 7, "server_ip": "1.2.3.7", "server_ipv6_net": "2a01:4f8::", "status": "ready"}],
+				"subnet": [{"ip": "10.0.0.0", "mask": 24, "gateway": "10.0.0.1"}],
+				"cloud_network": [{"id": 9, "ip": "10.1.0.0", "mask": 16, "gateway": "10.1.0.1"}]}`))
+		case "/vswitch/2":
+			w.Write([]byte(`{"id": 2, "name": "test", "vlan": 4001, "canceled": false, "server": [], "subnet": [], "cloud_network": []}`))
+		case "/vswitch/3":
+			w.Write([]byte(`{"id": 3, "name": "test", "vlan": 4002, "canceled": true, "server": [], "subnet": [], "cloud_network": []}`))
+		default:
+			http.Error(w, "Not Found", http.StatusNotFound)
+		}
+	}))
+}
+
+func TestDataSourceVSwitchRead(t *testing.T) {
+	server := newVSwitchesTestServer(t)
+	defer server.Close()
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	tests := []struct {
+		name       string
+		config     map[string]any
+		expectedID string
+		expectErr  bool
+	}{
+		{name: "by id", config: map[string]any{"id": "1"}, expectedID: "1"},
+		{name: "by name", config: map[string]any{"name": "prod"}, expectedID: "1"},
+		{name: "by vlan", config: map[string]any{"vlan": 4002}, expectedID: "3"},
+		{name: "by name and vlan", config: map[string]any{"name": "test", "vlan": 4001}, expectedID: "2"},
+		{name: "ambiguous name", config: map[string]any{"name": "test"}, expectErr: true},
+		{name: "no match", config: map[string]any{"name": "missing"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, dataVSwitch().Schema, tt.config)
+			diags := dataSourceVSwitchRead(context.Background(), d, client)
+			if tt.expectErr {
+				if !diags.HasError() {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("Unexpected error: %v", diags)
+			}
+			if d.Id() != tt.expectedID {
+				t.Fatalf("Expected ID %s, got %s", tt.expectedID, d.Id())
+			}
+		})
+	}
+
+	t.Run("nested fields", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, dataVSwitch().Schema, map[string]any{"id": "1"})
+		if diags := dataSourceVSwitchRead(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("Unexpected error: %v", diags)
+		}
+		if d.Get("servers.0.server_ip").(string) != "1.2.3.7" || d.Get("servers.0.status").(string) != "ready" {
+			t.Fatalf("Unexpected servers: %v", d.Get("servers"))
+		}
+		if d.Get("subnets.0.mask").(int) != 24 {
+			t.Fatalf("Unexpected subnets: %v", d.Get("subnets"))
+		}
+		if d.Get("cloud_networks.0.id").(int) != 9 {
+			t.Fatalf("Unexpected cloud networks: %v", d.Get("cloud_networks"))
+		}
+	})
+}
+
+func TestDataSourceVSwitchesRead(t *testing.T) {
+	server := newVSwitchesTestServer(t)
+	defer server.Close()
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	d := schema.TestResourceDataRaw(t, dataVSwitches().Schema, map[string]any{})
+	if diags := dataSourceVSwitchesRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	if d.Id() == "" {
+		t.Fatal("Expected ID to be set")
+	}
+	if d.Get("vswitches.#").(int) != 3 {
+		t.Fatalf("Expected 3 vSwitches, got %v", d.Get("vswitches"))
+	}
+	if d.Get("vswitches.0.servers.0.server_number").(int) != 7 {
+		t.Fatalf("Expected servers of the first vSwitch, got %v", d.Get("vswitches.0.servers"))
+	}
+	if !d.Get("vswitches.2.is_canceled").(bool) {
+		t.Fatalf("Expected third vSwitch to be canceled, got %v", d.Get("vswitches.2"))
+	}
+}
+
+func TestAccDataSourceVSwitch(t *testing.T) {
+	_, providerConfig := testAccRobotMock(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "hetznerrobot_vswitch" "test" {
+  name = "acc-test"
+  vlan = 4000
+
+  servers {
+    server_number = %d
+  }
+}
+
+data "hetznerrobot_vswitch" "by_id" {
+  id = hetznerrobot_vswitch.test.id
+}
+
+data "hetznerrobot_vswitch" "by_vlan" {
+  vlan = hetznerrobot_vswitch.test.vlan
+}
+
+data "hetznerrobot_vswitches" "all" {
+  depends_on = [hetznerrobot_vswitch.test]
+}
+`, testAccServerNumber),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.hetznerrobot_vswitch.by_id", "id", "hetznerrobot_vswitch.test", "id"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_vswitch.by_id", "name", "acc-test"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_vswitch.by_id", "servers.#", "1"),
+					resource.TestCheckResourceAttrPair("data.hetznerrobot_vswitch.by_vlan", "id", "hetznerrobot_vswitch.test", "id"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_vswitches.all", "vswitches.#", "1"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_vswitches.all", "vswitches.0.servers.0.server_ip", testAccServerIP),
+				),
+			},
+		},
+	})
+}
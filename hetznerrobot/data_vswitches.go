@@ -0,0 +1,110 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataVSwitches() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVSwitchesRead,
+		Description: "Lists all Hetzner Robot vSwitches of the account",
+		Schema: map[string]*schema.Schema{
+			"vswitches": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "vSwitch list",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "vSwitch ID",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "vSwitch name",
+						},
+						"vlan": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "VLAN ID",
+						},
+						"is_canceled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Cancellation status",
+						},
+						"servers": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Attached server list",
+							Elem:        dataVSwitchServerResource(),
+						},
+						"subnets": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Attached subnet list",
+							Elem:        dataVSwitchSubnetResource(),
+						},
+						"cloud_networks": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Attached cloud network list",
+							Elem:        dataVSwitchCloudNetworkResource(),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVSwitchesRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	c, ok := meta.(HetznerRobotClient)
+	if !ok {
+		return diag.Errorf("Unable to cast meta to HetznerRobotClient")
+	}
+
+	vSwitches, err := c.getVSwitches(ctx)
+	if err != nil {
+		return diag.Errorf("Unable to list VSwitches:\n\t %q", err)
+	}
+
+	// the list only contains the vSwitches themselves, servers, subnets and
+	// cloud networks are only returned per vSwitch
+	result := make([]map[string]any, 0, len(vSwitches))
+	ids := make([]string, 0, len(vSwitches))
+	for _, item := range vSwitches {
+		vSwitchID := strconv.Itoa(item.ID)
+		vSwitch, err := c.getVSwitch(ctx, vSwitchID)
+		if err != nil {
+			return diag.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err)
+		}
+		result = append(result, map[string]any{
+			"id":             vSwitch.ID,
+			"name":           vSwitch.Name,
+			"vlan":           vSwitch.Vlan,
+			"is_canceled":    vSwitch.Canceled,
+			"servers":        flattenVSwitchServers(vSwitch.Server, nil),
+			"subnets":        flattenVSwitchSubnets(vSwitch.Subnet),
+			"cloud_networks": flattenVSwitchCloudNetworks(vSwitch.CloudNetwork),
+		})
+		ids = append(ids, vSwitchID)
+	}
+
+	if err := d.Set("vswitches", result); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.Itoa(schema.HashString(strings.Join(ids, ","))))
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	return diags
+}
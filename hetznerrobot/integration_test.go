@@ -2,99 +2,30 @@ package hetznerrobot
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/robotmock"
 )
 
 // TestAuthenticationFlow tests the complete authentication flow from provider config to API call
 func TestAuthenticationFlow(t *testing.T) {
 	// Mock Hetzner Robot API server
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Check authentication
-		username, password, ok := r.BasicAuth()
-		if !ok {
-			t.Error("Missing basic auth")
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-
-		if username != "testuser" || password != "testpass" {
-			t.Errorf("Wrong credentials: %s:%s", username, password)
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-
-		// Route requests based on path
-		switch r.URL.Path {
-		case "/server":
-			// Mock server list response
-			servers := map[string]interface{}{
-				"server": []map[string]interface{}{
-					{
-						"server_ip":     "1.2.3.4",
-						"server_number": 12345,
-						"server_name":   "test-server",
-						"product":       "EX41",
-						"dc":           "FSN1-DC1",
-						"status":       "ready",
-						"canceled":     false,
-					},
-				},
-			}
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(servers)
-
-		case "/boot/1.2.3.4":
-			// Mock boot configuration response
-			boot := map[string]interface{}{
-				"boot": map[string]interface{}{
-					"linux": map[string]interface{}{
-						"active": true,
-						"os":     "ubuntu_20.04",
-						"arch":   "64",
-						"lang":   "en",
-					},
-					"rescue": map[string]interface{}{
-						"active": false,
-					},
-				},
-			}
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(boot)
-
-		case "/firewall/1.2.3.4":
-			// Mock firewall response
-			firewall := map[string]interface{}{
-				"firewall": map[string]interface{}{
-					"server_ip":     "1.2.3.4",
-					"status":        "active",
-					"whitelist_hos": true,
-					"rules": map[string]interface{}{
-						"input": []map[string]interface{}{
-							{
-								"name":       "SSH",
-								"src_ip":     "0.0.0.0/0",
-								"dst_port":   "22",
-								"action":     "accept",
-								"protocol":   "tcp",
-								"ip_version": "ipv4",
-							},
-						},
-					},
-				},
-			}
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(firewall)
-
-		default:
-			http.Error(w, "Not Found", http.StatusNotFound)
-		}
-	}))
+	mock := robotmock.New(robotmock.Config{Username: "testuser", Password: "testpass"})
+	mock.AddServer(robotmock.Server{Number: 12345, IP: "1.2.3.4", Name: "test-server", Product: "EX41"})
+	mock.SetBoot(12345, robotmock.Boot{Type: "linux", OS: "Ubuntu 22.04 LTS base", Arch: 64, Lang: "en"})
+	mock.SetFirewall(12345, robotmock.Firewall{
+		Status:       "active",
+		WhitelistHOS: true,
+		Port:         "main",
+		Input: []robotmock.FirewallRule{
+			{Name: "SSH", SrcIP: "0.0.0.0/0", DstPort: "22", Action: "accept", Protocol: "tcp", IPVersion: "ipv4"},
+		},
+	})
+	server := httptest.NewServer(mock)
 	defer server.Close()
 
 	// Test provider configuration with mock server
@@ -239,75 +170,25 @@ func TestRealWorldScenario(t *testing.T) {
 	}
 
 	// Create a more comprehensive mock API
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Authenticate
-		username, password, ok := r.BasicAuth()
-		if !ok {
-			http.Error(w, "No auth", http.StatusUnauthorized)
-			return
-		}
-
-		// In a real test, you might validate against test credentials
-		if username == "" || password == "" {
-			http.Error(w, "Invalid auth", http.StatusUnauthorized)
-			return
-		}
-
-		// Mock various API endpoints
-		switch {
-		case r.URL.Path == "/server" && r.Method == "GET":
-			response := map[string]interface{}{
-				"server": []map[string]interface{}{
-					{
-						"server_ip":     "192.168.1.1",
-						"server_number": 54321,
-						"server_name":   "production-server",
-						"product":       "AX41",
-						"dc":           "FSN1-DC14",
-						"status":       "ready",
-						"canceled":     false,
-						"paid_until":   "2024-12-31",
-					},
-				},
-			}
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(response)
-
-		case r.URL.Path == "/firewall/192.168.1.1" && r.Method == "GET":
-			response := map[string]interface{}{
-				"firewall": map[string]interface{}{
-					"server_ip":     "192.168.1.1",
-					"status":        "active",
-					"whitelist_hos": true,
-					"rules": map[string]interface{}{
-						"input": []map[string]interface{}{
-							{
-								"name":       "SSH Access",
-								"src_ip":     "10.0.0.0/8",
-								"dst_port":   "22",
-								"action":     "accept",
-								"protocol":   "tcp",
-								"ip_version": "ipv4",
-							},
-							{
-								"name":       "HTTPS",
-								"src_ip":     "0.0.0.0/0",
-								"dst_port":   "443",
-								"action":     "accept",
-								"protocol":   "tcp",
-								"ip_version": "ipv4",
-							},
-						},
-					},
-				},
-			}
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(response)
-
-		default:
-			http.Error(w, "Not Found", http.StatusNotFound)
-		}
-	}))
+	mock := robotmock.New(robotmock.Config{Username: "realuser", Password: "realpass"})
+	mock.AddServer(robotmock.Server{
+		Number:     54321,
+		IP:         "192.168.1.1",
+		Name:       "production-server",
+		Product:    "AX41",
+		DataCenter: "FSN1-DC14",
+		PaidUntil:  "2024-12-31",
+	})
+	mock.SetFirewall(54321, robotmock.Firewall{
+		Status:       "active",
+		WhitelistHOS: true,
+		Port:         "main",
+		Input: []robotmock.FirewallRule{
+			{Name: "SSH Access", SrcIP: "10.0.0.0/8", DstPort: "22", Action: "accept", Protocol: "tcp", IPVersion: "ipv4"},
+			{Name: "HTTPS", SrcIP: "0.0.0.0/0", DstPort: "443", Action: "accept", Protocol: "tcp", IPVersion: "ipv4"},
+		},
+	})
+	server := httptest.NewServer(mock)
 	defer server.Close()
 
 	// Test the complete flow
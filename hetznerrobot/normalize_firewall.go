@@ -0,0 +1,95 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+)
+
+// Robot reads firewall rules back in a different but equivalent form than
+// they were written in. canonicalFirewallRule maps both forms to the same
+// value, so state can be compared with configuration without perpetual diffs.
+func canonicalFirewallRule(rule HetznerRobotFirewallRule) HetznerRobotFirewallRule {
+	return HetznerRobotFirewallRule{
+		Name:      rule.Name,
+		DstIP:     canonicalFirewallRuleField("dst_ip", rule.DstIP),
+		DstPort:   canonicalFirewallRuleField("dst_port", rule.DstPort),
+		SrcIP:     canonicalFirewallRuleField("src_ip", rule.SrcIP),
+		SrcPort:   canonicalFirewallRuleField("src_port", rule.SrcPort),
+		Protocol:  canonicalFirewallRuleField("protocol", rule.Protocol),
+		TCPFlags:  canonicalFirewallRuleField("tcp_flags", rule.TCPFlags),
+		Action:    canonicalFirewallRuleField("action", rule.Action),
+		IPVersion: canonicalFirewallRuleField("ip_version", rule.IPVersion),
+	}
+}
+
+func canonicalFirewallRules(rules []HetznerRobotFirewallRule) []HetznerRobotFirewallRule {
+	result := make([]HetznerRobotFirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, canonicalFirewallRule(rule))
+	}
+	return result
+}
+
+func canonicalFirewallRuleField(field string, value string) string {
+	value = strings.TrimSpace(value)
+
+	switch field {
+	case "ip_version":
+		// setFirewall sends ipv4 when no version is given
+		if value == "" {
+			return "ipv4"
+		}
+		return strings.ToLower(value)
+	case "src_ip", "dst_ip":
+		prefix, err := parseFirewallPrefix(value)
+		if err != nil {
+			return value
+		}
+		// a rule without address matches any address
+		if prefix.Bits() == 0 {
+			return ""
+		}
+		return prefix.String()
+	case "src_port", "dst_port":
+		// a rule without port matches any port
+		if value == "0-65535" {
+			return ""
+		}
+		from, to, isRange := strings.Cut(value, "-")
+		if isRange && from == to {
+			return from
+		}
+		return value
+	case "protocol", "tcp_flags", "action":
+		return strings.ToLower(value)
+	}
+	return value
+}
+
+// canonicalFirewallRuleFieldModifier keeps the prior value of a rule field
+// if the planned one is equivalent for Robot, so rules read back from Robot
+// do not show up as changes.
+type canonicalFirewallRuleFieldModifier struct {
+	field string
+}
+
+var _ planmodifier.String = canonicalFirewallRuleFieldModifier{}
+
+func (m canonicalFirewallRuleFieldModifier) Description(context.Context) string {
+	return "Keeps the prior value if Robot treats it as equal to the planned value."
+}
+
+func (m canonicalFirewallRuleFieldModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m canonicalFirewallRuleFieldModifier) PlanModifyString(_ context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+	if canonicalFirewallRuleField(m.field, req.StateValue.ValueString()) == canonicalFirewallRuleField(m.field, req.PlanValue.ValueString()) {
+		resp.PlanValue = req.StateValue
+	}
+}
@@ -0,0 +1,145 @@
+package hetznerrobot
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCanonicalFirewallRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     HetznerRobotFirewallRule
+		expected HetznerRobotFirewallRule
+	}{
+		{
+			name:     "missing ip_version",
+			rule:     HetznerRobotFirewallRule{Action: "accept"},
+			expected: HetznerRobotFirewallRule{Action: "accept", IPVersion: "ipv4"},
+		},
+		{
+			name:     "full port range",
+			rule:     HetznerRobotFirewallRule{SrcPort: "0-65535", DstPort: "0-65535", IPVersion: "ipv4"},
+			expected: HetznerRobotFirewallRule{IPVersion: "ipv4"},
+		},
+		{
+			name:     "single port range",
+			rule:     HetznerRobotFirewallRule{DstPort: "22-22", IPVersion: "ipv4"},
+			expected: HetznerRobotFirewallRule{DstPort: "22", IPVersion: "ipv4"},
+		},
+		{
+			name:     "port range",
+			rule:     HetznerRobotFirewallRule{DstPort: "32768-65535", IPVersion: "ipv4"},
+			expected: HetznerRobotFirewallRule{DstPort: "32768-65535", IPVersion: "ipv4"},
+		},
+		{
+			name:     "any ipv4 address",
+			rule:     HetznerRobotFirewallRule{SrcIP: "0.0.0.0/0", DstIP: "0.0.0.0/0", IPVersion: "ipv4"},
+			expected: HetznerRobotFirewallRule{IPVersion: "ipv4"},
+		},
+		{
+			name:     "host address",
+			rule:     HetznerRobotFirewallRule{SrcIP: "1.2.3.4", DstIP: "1.2.3.4/32", IPVersion: "ipv4"},
+			expected: HetznerRobotFirewallRule{SrcIP: "1.2.3.4/32", DstIP: "1.2.3.4/32", IPVersion: "ipv4"},
+		},
+		{
+			name:     "ipv6 addresses",
+			rule:     HetznerRobotFirewallRule{SrcIP: "2001:db8::/32", DstIP: "::/0", IPVersion: "ipv6"},
+			expected: HetznerRobotFirewallRule{SrcIP: "2001:db8::/32", IPVersion: "ipv6"},
+		},
+		{
+			name:     "case and whitespace",
+			rule:     HetznerRobotFirewallRule{Protocol: "TCP", TCPFlags: " SYN|ACK ", Action: "Accept", IPVersion: "IPv4"},
+			expected: HetznerRobotFirewallRule{Protocol: "tcp", TCPFlags: "syn|ack", Action: "accept", IPVersion: "ipv4"},
+		},
+		{
+			name:     "invalid address kept",
+			rule:     HetznerRobotFirewallRule{SrcIP: "not-an-ip", IPVersion: "ipv4"},
+			expected: HetznerRobotFirewallRule{SrcIP: "not-an-ip", IPVersion: "ipv4"},
+		},
+		{
+			name:     "name kept",
+			rule:     HetznerRobotFirewallRule{Name: "Allow SSH", IPVersion: "ipv4"},
+			expected: HetznerRobotFirewallRule{Name: "Allow SSH", IPVersion: "ipv4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := canonicalFirewallRule(tt.rule)
+			if result != tt.expected {
+				t.Fatalf("Expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCanonicalFirewallRuleFromJSON(t *testing.T) {
+	// Robot returns null for unset rule fields
+	var rule HetznerRobotFirewallRule
+	err := json.Unmarshal([]byte(`{"name": "SSH", "ip_version": null, "src_ip": null, "src_port": null, "dst_port": "22", "protocol": "tcp", "tcp_flags": null, "action": "accept"}`), &rule)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := HetznerRobotFirewallRule{Name: "SSH", DstPort: "22", Protocol: "tcp", Action: "accept", IPVersion: "ipv4"}
+	if result := canonicalFirewallRule(rule); result != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestCanonicalFirewallRuleFieldModifier(t *testing.T) {
+	tests := []struct {
+		field string
+		prior string
+		plan  string
+		keep  bool
+	}{
+		{"src_port", "", "0-65535", true},
+		{"dst_port", "22", "22-22", true},
+		{"dst_port", "22", "23", false},
+		{"src_ip", "", "0.0.0.0/0", true},
+		{"src_ip", "1.2.3.4/32", "1.2.3.4", true},
+		{"src_ip", "1.2.3.4/32", "1.2.3.5", false},
+		{"ip_version", "", "ipv4", true},
+		{"ip_version", "ipv4", "ipv6", false},
+		{"protocol", "tcp", "TCP", true},
+		{"protocol", "", "tcp", false},
+		{"src_ip", "", "2001:db8::/32", false},
+		{"dst_ip", "", "2001:db8::1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field+" "+tt.prior+" "+tt.plan, func(t *testing.T) {
+			req := planmodifier.StringRequest{
+				StateValue: types.StringValue(tt.prior),
+				PlanValue:  types.StringValue(tt.plan),
+			}
+			resp := planmodifier.StringResponse{PlanValue: req.PlanValue}
+			canonicalFirewallRuleFieldModifier{field: tt.field}.PlanModifyString(context.Background(), req, &resp)
+
+			expected := tt.plan
+			if tt.keep {
+				expected = tt.prior
+			}
+			if resp.PlanValue.ValueString() != expected {
+				t.Fatalf("Expected plan %q, got %q", expected, resp.PlanValue.ValueString())
+			}
+		})
+	}
+
+	t.Run("new rule", func(t *testing.T) {
+		req := planmodifier.StringRequest{
+			StateValue: types.StringNull(),
+			PlanValue:  types.StringValue("22-22"),
+		}
+		resp := planmodifier.StringResponse{PlanValue: req.PlanValue}
+		canonicalFirewallRuleFieldModifier{field: "dst_port"}.PlanModifyString(context.Background(), req, &resp)
+		if resp.PlanValue.ValueString() != "22-22" {
+			t.Fatalf("Expected the planned value, got %q", resp.PlanValue.ValueString())
+		}
+	})
+}
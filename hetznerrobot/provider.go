@@ -2,9 +2,14 @@ package hetznerrobot
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Provider -.
@@ -21,21 +26,114 @@ func Provider() *schema.Provider {
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_PASSWORD", nil),
 			},
+			"credentials_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_CREDENTIALS_FILE", nil),
+				Description: "INI or YAML file with named credential profiles, used for credentials not given by username and password",
+			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_PROFILE", defaultCredentialsProfile),
+				Description: "Profile to read from credentials_file",
+			},
+			"credential_process": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_CREDENTIAL_PROCESS", nil),
+				Description: "Command printing the credentials as JSON object with username and password, used instead of credentials_file",
+			},
+			"validate_credentials": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Check the credentials with a request to Robot when the provider is configured",
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Reject every Robot API request that could change something, so plans and data sources can safely run with production credentials",
+			},
 			"url": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_URL", "https://robot-ws.your-server.de"),
 			},
+			"request_timeout": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("HETZNERROBOT_REQUEST_TIMEOUT", defaultRequestTimeout.String()),
+				ValidateDiagFunc: validateDuration,
+				Description:      "Timeout of a single Robot API request, e.g. \"30s\" or \"2m\"",
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_PROXY_URL", nil),
+				Description: "Proxy to send Robot API requests through. Defaults to the HTTPS_PROXY and NO_PROXY environment variables",
+			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("HETZNERROBOT_CA_CERT_FILE", nil),
+				ConflictsWith: []string{"ca_cert_pem"},
+				Description:   "Path to a PEM file with CA certificates to trust in addition to the system ones",
+			},
+			"ca_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_file"},
+				Description:   "PEM encoded CA certificates to trust in addition to the system ones",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Skip TLS certificate verification. Only meant for local stand-ins of the Robot API",
+			},
+			"max_concurrent_requests": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          defaultMaxConcurrentRequests,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "Maximum number of Robot API requests in flight across all resources",
+			},
+			"requests_per_minute": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+				Description:      "Maximum number of Robot API requests per minute across all resources, 0 for no limit. Robot's documented per-endpoint limits always apply",
+			},
+			"response_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Share GET responses between resources and data sources reading the same objects. Writes invalidate the cached responses of the objects they change, but not of other objects Robot changes along with them, e.g. the boot configuration of a reset server",
+			},
+			"response_cache_ttl": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          defaultResponseCacheTTL.String(),
+				ValidateDiagFunc: validateDuration,
+				Description:      "How long GET responses are shared, e.g. \"30s\"",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"hetznerrobot_boot":     resourceBoot(),
-			"hetznerrobot_firewall": resourceFirewall(),
-			"hetznerrobot_vswitch":  resourceVSwitch(),
+			"hetznerrobot_boot":               resourceBoot(),
+			"hetznerrobot_vswitch":            resourceVSwitch(),
+			"hetznerrobot_vswitch_attachment": resourceVSwitchAttachment(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"hetznerrobot_boot":    dataBoot(),
-			"hetznerrobot_server":  dataServer(),
-			"hetznerrobot_vswitch": dataVSwitch(),
+			"hetznerrobot_account":        dataAccount(),
+			"hetznerrobot_boot":           dataBoot(),
+			"hetznerrobot_firewall":       dataFirewall(),
+			"hetznerrobot_firewall_rules": dataFirewallRules(),
+			"hetznerrobot_server":         dataServer(),
+			"hetznerrobot_vswitch":        dataVSwitch(),
+			"hetznerrobot_vswitches":      dataVSwitches(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -57,6 +155,14 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (any, diag.D
 		return nil, diag.Errorf("url must be a string")
 	}
 
+	credentialsFile, _ := d.Get("credentials_file").(string)
+	profile, _ := d.Get("profile").(string)
+	credentialProcess, _ := d.Get("credential_process").(string)
+	username, password, err := resolveCredentials(ctx, username, password, credentialsFile, profile, credentialProcess)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
 	if username == "" {
 		return nil, diag.Errorf("username is required for Hetzner Robot authentication")
 	}
@@ -64,8 +170,77 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (any, diag.D
 		return nil, diag.Errorf("password is required for Hetzner Robot authentication")
 	}
 
+	requestTimeout, _ := d.Get("request_timeout").(string)
+	timeout, err := time.ParseDuration(requestTimeout)
+	if err != nil {
+		return nil, diag.Errorf("request_timeout is not a valid duration: %s", err)
+	}
+
+	proxyURL, _ := d.Get("proxy_url").(string)
+	caCertFile, _ := d.Get("ca_cert_file").(string)
+	caCertPEM, _ := d.Get("ca_cert_pem").(string)
+	caCert, err := readCACert(caCertFile, caCertPEM)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	insecureSkipVerify, _ := d.Get("insecure_skip_verify").(bool)
+
+	httpClient, err := NewHTTPClient(HTTPClientOptions{
+		Timeout:            timeout,
+		ProxyURL:           proxyURL,
+		CACertPEM:          caCert,
+		InsecureSkipVerify: insecureSkipVerify,
+	})
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	maxConcurrentRequests, _ := d.Get("max_concurrent_requests").(int)
+	requestsPerMinute, _ := d.Get("requests_per_minute").(int)
+
 	client := NewHetznerRobotClient(username, password, url)
+	client.httpClient = httpClient
+	client.readOnly, _ = d.Get("read_only").(bool)
+	client.limiter = newRequestLimiter(maxConcurrentRequests, requestsPerMinute)
+
+	if responseCache, _ := d.Get("response_cache").(bool); responseCache {
+		responseCacheTTL, _ := d.Get("response_cache_ttl").(string)
+		ttl, err := time.ParseDuration(responseCacheTTL)
+		if err != nil {
+			return nil, diag.Errorf("response_cache_ttl is not a valid duration: %s", err)
+		}
+		client.cache = newResponseCache(ttl)
+	}
+
+	if validateCredentials, _ := d.Get("validate_credentials").(bool); validateCredentials {
+		if err := client.validateCredentials(ctx); errors.Is(err, errInvalidCredentials) {
+			return nil, diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "Invalid Hetzner Robot credentials",
+				Detail: fmt.Sprintf("Robot rejected the webservice user %q at %s. ", username, url) +
+					"Webservice users are created in Robot under Settings > Webservice and app settings and differ from the Robot login.",
+			}}
+		} else if err != nil {
+			return nil, diag.Errorf("Unable to validate Hetzner Robot credentials: %s", err)
+		}
+	}
 
 	var diags diag.Diagnostics
 	return client, diags
 }
+
+func validateDuration(v any, path cty.Path) diag.Diagnostics {
+	value, ok := v.(string)
+	if !ok {
+		return diag.Errorf("expected type of %v to be string", v)
+	}
+	if duration, err := time.ParseDuration(value); err != nil || duration <= 0 {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("Invalid duration %q", value),
+			Detail:        "Expected a positive duration such as \"30s\" or \"2m\".",
+			AttributePath: path,
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	sdkschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ProviderServer serves the SDK provider and the plugin framework provider
+// as one protocol 6 provider. New resources and data sources are written
+// with the framework, SDK ones move over one at a time without changing
+// their state.
+func ProviderServer(ctx context.Context) (func() tfprotov6.ProviderServer, error) {
+	sdkProvider := Provider()
+
+	upgradedSDKServer, err := tf5to6server.UpgradeServer(ctx, sdkProvider.GRPCProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(ctx,
+		func() tfprotov6.ProviderServer { return upgradedSDKServer },
+		providerserver.NewProtocol6(newFrameworkProvider(sdkProvider)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return muxServer.ProviderServer, nil
+}
+
+// frameworkProvider is the plugin framework half of the provider.
+//
+// Both halves receive the same provider configuration. The SDK provider is
+// configured first and validates it, so the framework provider reuses the
+// client built there instead of resolving credentials a second time.
+type frameworkProvider struct {
+	sdkProvider *sdkschema.Provider
+}
+
+var _ provider.Provider = (*frameworkProvider)(nil)
+
+func newFrameworkProvider(sdkProvider *sdkschema.Provider) provider.Provider {
+	return &frameworkProvider{sdkProvider: sdkProvider}
+}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "hetznerrobot"
+}
+
+// Schema must match the SDK provider schema exactly, the mux server rejects
+// providers that differ. Defaults and validation stay with the SDK provider.
+func (p *frameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	sdkSchema := p.sdkProvider.Schema
+	attributes := make(map[string]schema.Attribute, len(sdkSchema))
+	for name, attribute := range sdkSchema {
+		switch attribute.Type {
+		case sdkschema.TypeString:
+			attributes[name] = schema.StringAttribute{
+				Optional:    attribute.Optional,
+				Sensitive:   attribute.Sensitive,
+				Description: attribute.Description,
+			}
+		case sdkschema.TypeBool:
+			attributes[name] = schema.BoolAttribute{
+				Optional:    attribute.Optional,
+				Sensitive:   attribute.Sensitive,
+				Description: attribute.Description,
+			}
+		case sdkschema.TypeInt:
+			attributes[name] = schema.Int64Attribute{
+				Optional:    attribute.Optional,
+				Sensitive:   attribute.Sensitive,
+				Description: attribute.Description,
+			}
+		default:
+			resp.Diagnostics.AddError(
+				"Unsupported provider attribute",
+				fmt.Sprintf("Provider attribute %q has type %s, which has no plugin framework counterpart yet.", name, attribute.Type),
+			)
+		}
+	}
+	resp.Schema = schema.Schema{Attributes: attributes}
+}
+
+func (p *frameworkProvider) Configure(_ context.Context, _ provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	client, ok := p.sdkProvider.Meta().(HetznerRobotClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unconfigured Hetzner Robot client",
+			"The SDK provider must be configured before the plugin framework provider. This is a bug in the provider.",
+		)
+		return
+	}
+	resp.DataSourceData = client
+	resp.ResourceData = client
+}
+
+func (p *frameworkProvider) Resources(context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newFirewallResource,
+	}
+}
+
+func (p *frameworkProvider) DataSources(context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		newServerMarketProductsDataSource,
+	}
+}
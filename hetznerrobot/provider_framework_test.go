@@ -0,0 +1,81 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestProviderServerSchema(t *testing.T) {
+	providerServer, err := ProviderServer(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	resp, err := providerServer().GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, diagnostic := range resp.Diagnostics {
+		t.Errorf("Unexpected diagnostic: %s: %s", diagnostic.Summary, diagnostic.Detail)
+	}
+
+	sdkProvider := Provider()
+	if got, want := len(resp.Provider.Block.Attributes), len(sdkProvider.Schema); got != want {
+		t.Errorf("Expected %d provider attributes, got %d", want, got)
+	}
+	for name := range sdkProvider.ResourcesMap {
+		if _, ok := resp.ResourceSchemas[name]; !ok {
+			t.Errorf("Expected resource %s to be served", name)
+		}
+	}
+	for name := range sdkProvider.DataSourcesMap {
+		if _, ok := resp.DataSourceSchemas[name]; !ok {
+			t.Errorf("Expected data source %s to be served", name)
+		}
+	}
+	for _, newResource := range newFrameworkProvider(sdkProvider).Resources(context.Background()) {
+		var metadata resource.MetadataResponse
+		newResource().Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "hetznerrobot"}, &metadata)
+		if _, ok := resp.ResourceSchemas[metadata.TypeName]; !ok {
+			t.Errorf("Expected resource %s to be served", metadata.TypeName)
+		}
+	}
+}
+
+func TestFrameworkProviderConfigure(t *testing.T) {
+	sdkProvider := Provider()
+	frameworkProvider := newFrameworkProvider(sdkProvider)
+
+	var resp provider.ConfigureResponse
+	frameworkProvider.Configure(context.Background(), provider.ConfigureRequest{}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected an error before the SDK provider is configured")
+	}
+
+	diags := sdkProvider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"username":  "testuser",
+		"password":  "testpass",
+		"read_only": true,
+	}))
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	resp = provider.ConfigureResponse{}
+	frameworkProvider.Configure(context.Background(), provider.ConfigureRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", resp.Diagnostics)
+	}
+	client, ok := resp.ResourceData.(HetznerRobotClient)
+	if !ok || client.username != "testuser" || !client.readOnly {
+		t.Fatalf("Expected the client of the SDK provider, got %+v", resp.ResourceData)
+	}
+	if _, ok := resp.DataSourceData.(HetznerRobotClient); !ok {
+		t.Fatalf("Expected the client of the SDK provider for data sources, got %+v", resp.DataSourceData)
+	}
+}
@@ -2,10 +2,18 @@ package hetznerrobot
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/robotmock"
 )
 
 func TestProvider(t *testing.T) {
@@ -208,6 +216,39 @@ func TestProviderDefaultURL(t *testing.T) {
 	}
 }
 
+func TestProviderResponseCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected bool
+	}{
+		{"disabled by default", map[string]interface{}{}, false},
+		{"enabled", map[string]interface{}{"response_cache": true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{
+				"username":             "testuser",
+				"password":             "testpass",
+				"validate_credentials": false,
+			}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+			resourceData := schema.TestResourceDataRaw(t, Provider().Schema, config)
+
+			client, diags := providerConfigure(context.Background(), resourceData)
+			if diags.HasError() {
+				t.Fatalf("Unexpected error: %v", diags)
+			}
+			if hetznerClient := client.(HetznerRobotClient); (hetznerClient.cache != nil) != tt.expected {
+				t.Fatalf("Expected response cache enabled=%v, got %v", tt.expected, hetznerClient.cache != nil)
+			}
+		})
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("HETZNERROBOT_USERNAME"); v == "" {
 		t.Fatal("HETZNERROBOT_USERNAME must be set for acceptance tests")
@@ -223,10 +264,199 @@ func testAccProviders() map[string]*schema.Provider {
 	}
 }
 
-func testAccProviderFactories() map[string]func() (*schema.Provider, error) {
-	return map[string]func() (*schema.Provider, error){
-		"hetznerrobot": func() (*schema.Provider, error) {
-			return Provider(), nil
+// testAccProtoV6ProviderFactories serves the provider the way main does,
+// through the mux of the SDK and plugin framework providers.
+func testAccProtoV6ProviderFactories() map[string]func() (tfprotov6.ProviderServer, error) {
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"hetznerrobot": func() (tfprotov6.ProviderServer, error) {
+			providerServer, err := ProviderServer(context.Background())
+			if err != nil {
+				return nil, err
+			}
+			return providerServer(), nil
+		},
+	}
+}
+
+const (
+	testAccServerNumber  = 1001
+	testAccServerIP      = "198.51.100.1"
+	testAccServerNumber2 = 1002
+	testAccServerIP2     = "198.51.100.2"
+)
+
+// testAccRobotMock starts a fake Robot webservice with two servers for
+// acceptance tests and returns it with a provider block pointing at it.
+func testAccRobotMock(t *testing.T) (*robotmock.Mock, string) {
+	t.Helper()
+
+	mock := robotmock.New(robotmock.Config{
+		Username:    "acc-user",
+		Password:    "acc-pass",
+		SettlePolls: robotmock.DefaultSettlePolls,
+		RateLimits:  robotmock.DefaultRateLimits(),
+	})
+	mock.AddServer(robotmock.Server{Number: testAccServerNumber, IP: testAccServerIP})
+	mock.AddServer(robotmock.Server{Number: testAccServerNumber2, IP: testAccServerIP2})
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+
+	setFirewallPollInterval(t, 10*time.Millisecond)
+	setVSwitchPollInterval(t, 10*time.Millisecond)
+
+	return mock, fmt.Sprintf(`
+provider "hetznerrobot" {
+  url      = %q
+  username = "acc-user"
+  password = "acc-pass"
+}
+`, server.URL)
+}
+
+func TestProviderConfigureHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caCertFile, testServerCACertPEM(t, server), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		config    map[string]any
+		expectErr bool
+	}{
+		{
+			name:   "ca_cert_pem",
+			config: map[string]any{"ca_cert_pem": string(testServerCACertPEM(t, server))},
+		},
+		{
+			name:   "ca_cert_file",
+			config: map[string]any{"ca_cert_file": caCertFile},
+		},
+		{
+			name:   "insecure_skip_verify",
+			config: map[string]any{"insecure_skip_verify": true, "request_timeout": "5s"},
 		},
+		{
+			name:      "missing ca_cert_file",
+			config:    map[string]any{"ca_cert_file": filepath.Join(t.TempDir(), "missing.pem")},
+			expectErr: true,
+		},
+		{
+			name:      "invalid proxy_url",
+			config:    map[string]any{"proxy_url": "proxy:3128"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["username"] = "testuser"
+			tt.config["password"] = "testpass"
+			tt.config["url"] = server.URL
+			resourceData := schema.TestResourceDataRaw(t, Provider().Schema, tt.config)
+
+			client, diags := providerConfigure(context.Background(), resourceData)
+			if tt.expectErr {
+				if !diags.HasError() {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("Unexpected error: %v", diags)
+			}
+
+			hetznerClient := client.(HetznerRobotClient)
+			if _, err := hetznerClient.makeAPICall(context.Background(), "GET", server.URL+"/server", nil, []int{http.StatusOK}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateDuration(t *testing.T) {
+	for value, valid := range map[string]bool{"30s": true, "2m": true, "0s": false, "-1s": false, "30": false} {
+		if diags := validateDuration(value, cty.Path{}); diags.HasError() == valid {
+			t.Fatalf("Expected %q valid=%v, got %v", value, valid, diags)
+		}
+	}
+}
+
+func TestProviderConfigureCredentialsFile(t *testing.T) {
+	credentialsFile := writeCredentialsFile(t, "credentials.yml", `
+ci:
+  username: file-user
+  password: file-pass
+`)
+
+	resourceData := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"credentials_file": credentialsFile,
+		"profile":          "ci",
+	})
+	client, diags := providerConfigure(context.Background(), resourceData)
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	hetznerClient := client.(HetznerRobotClient)
+	if hetznerClient.username != "file-user" || hetznerClient.password != "file-pass" {
+		t.Fatalf("Expected credentials from file, got %s/%s", hetznerClient.username, hetznerClient.password)
+	}
+}
+
+func TestProviderConfigureValidateCredentials(t *testing.T) {
+	server := newAccountTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		password string
+		validate bool
+		errMsg   string
+	}{
+		{name: "valid credentials", password: "pass", validate: true},
+		{name: "invalid credentials", password: "wrong", validate: true, errMsg: "Invalid Hetzner Robot credentials"},
+		{name: "validation disabled", password: "wrong", validate: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resourceData := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+				"username":             "#ws+user",
+				"password":             tt.password,
+				"url":                  server.URL,
+				"validate_credentials": tt.validate,
+			})
+			_, diags := providerConfigure(context.Background(), resourceData)
+			if tt.errMsg == "" {
+				if diags.HasError() {
+					t.Fatalf("Unexpected error: %v", diags)
+				}
+				return
+			}
+			if !diags.HasError() || diags[0].Summary != tt.errMsg {
+				t.Fatalf("Expected error %q, got: %v", tt.errMsg, diags)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureReadOnly(t *testing.T) {
+	resourceData := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"username":  "testuser",
+		"password":  "testpass",
+		"read_only": true,
+	})
+	client, diags := providerConfigure(context.Background(), resourceData)
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if !client.(HetznerRobotClient).readOnly {
+		t.Fatal("Expected client to be read only")
 	}
 }
@@ -3,13 +3,14 @@ package hetznerrobot
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceBoot() *schema.Resource {
-	return &schema.Resource{
+	return withStateUpgraderV0(&schema.Resource{
 		CreateContext: resourceBootCreate,
 		ReadContext:   resourceBootRead,
 		UpdateContext: resourceBootUpdate,
@@ -73,7 +74,7 @@ func resourceBoot() *schema.Resource {
 				Sensitive:   true,
 			},
 		},
-	}
+	}, resourceBootV0(), resourceBootStateUpgradeV0)
 }
 
 func resourceBootImportState(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
@@ -82,6 +83,7 @@ func resourceBootImportState(ctx context.Context, d *schema.ResourceData, meta a
 		return nil, fmt.Errorf("unable to cast meta to HetznerRobotClient")
 	}
 
+	// Robot accepts the server number or the server IP
 	serverID := d.Id()
 
 	boot, err := c.getBoot(ctx, serverID)
@@ -96,7 +98,7 @@ func resourceBootImportState(ctx context.Context, d *schema.ResourceData, meta a
 	_ = d.Set("language", boot.Language)
 	_ = d.Set("operating_system", boot.OperatingSystem)
 	_ = d.Set("password", boot.Password)
-	_ = d.Set("server_id", serverID)
+	_ = d.Set("server_id", boot.ServerID)
 
 	results := make([]*schema.ResourceData, 1)
 	results[0] = d
@@ -109,7 +111,8 @@ func resourceBootCreate(ctx context.Context, d *schema.ResourceData, meta any) d
 		return diag.Errorf("Unable to cast meta to HetznerRobotClient")
 	}
 
-	serverID := d.Id()
+	serverNumber, _ := d.Get("server_id").(int)
+	serverID := strconv.Itoa(serverNumber)
 	activeBootProfile, _ := d.Get("active_profile").(string)
 	arch, _ := d.Get("architecture").(string)
 	os, _ := d.Get("operating_system").(string)
@@ -164,6 +167,7 @@ func resourceBootRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 	_ = d.Set("language", boot.Language)
 	_ = d.Set("operating_system", boot.OperatingSystem)
 	_ = d.Set("password", boot.Password)
+	_ = d.Set("server_id", boot.ServerID)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -197,6 +201,20 @@ func resourceBootUpdate(ctx context.Context, d *schema.ResourceData, meta any) d
 		}
 	}
 
+	// Robot keeps an active configuration as it is and rejects activating a
+	// different one, so the active configuration is deactivated first. It is
+	// looked up instead of taken from state, as it may have been deactivated
+	// meanwhile, e.g. Robot does so once a rescue system has been booted.
+	boot, err := c.getBoot(withoutResponseCache(ctx), serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if boot.ActiveProfile != "" {
+		if err := c.deleteBootProfile(ctx, serverID, boot.ActiveProfile); err != nil {
+			return diag.Errorf("Unable to deactivate boot profile %s:\n\t %q", boot.ActiveProfile, err)
+		}
+	}
+
 	bootProfile, err := c.setBootProfile(ctx, serverID, activeBootProfile, arch, os, lang, authorizedKeys)
 	if err != nil {
 		return diag.FromErr(err)
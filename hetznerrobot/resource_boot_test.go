@@ -0,0 +1,285 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/robotmock"
+)
+
+const testAccBootKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGb0pUnrSsoU6Ue2ygdajOtzEKPBF7XGr+OIRYMTsiT3 acc"
+
+func TestAccResourceBoot(t *testing.T) {
+	mock, providerConfig := testAccRobotMock(t)
+	key, err := mock.AddKey("acc", testAccBootKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "hetznerrobot_boot" "test" {
+  server_id        = %d
+  active_profile   = "rescue"
+  architecture     = "64"
+  operating_system = "linux"
+}
+`, testAccServerNumber),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetznerrobot_boot.test", "id", strconv.Itoa(testAccServerNumber)),
+					resource.TestCheckResourceAttr("hetznerrobot_boot.test", "ipv4_address", testAccServerIP),
+					resource.TestCheckResourceAttrSet("hetznerrobot_boot.test", "password"),
+					testAccCheckBoot(mock, robotmock.Boot{Type: "rescue", OS: "linux", Arch: 64}),
+				),
+			},
+			{
+				ResourceName:      "hetznerrobot_boot.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "hetznerrobot_boot" "test" {
+  server_id        = %d
+  active_profile   = "linux"
+  architecture     = "64"
+  operating_system = "Debian 12 base"
+  language         = "en"
+  authorized_keys  = [%q]
+}
+`, testAccServerNumber, key.Fingerprint),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetznerrobot_boot.test", "active_profile", "linux"),
+					resource.TestCheckResourceAttr("hetznerrobot_boot.test", "password", ""),
+					testAccCheckBoot(mock, robotmock.Boot{
+						Type:           "linux",
+						OS:             "Debian 12 base",
+						Arch:           64,
+						Lang:           "en",
+						AuthorizedKeys: []string{key.Fingerprint},
+					}),
+				),
+			},
+			{
+				ResourceName:      "hetznerrobot_boot.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Robot does not return the fingerprints of authorized keys
+				ImportStateVerifyIgnore: []string{"authorized_keys"},
+			},
+		},
+	})
+}
+
+func TestAccDataSourceBoot(t *testing.T) {
+	mock, providerConfig := testAccRobotMock(t)
+	mock.SetBoot(testAccServerNumber, robotmock.Boot{Type: "rescue", OS: "vkvm", Arch: 64, Password: "secret"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+data "hetznerrobot_boot" "test" {
+  server_ip = %q
+}
+`, testAccServerIP),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetznerrobot_boot.test", "active_profile", "rescue"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_boot.test", "operating_system", "vkvm"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_boot.test", "architecture", "64"),
+					resource.TestCheckResourceAttr("data.hetznerrobot_boot.test", "password", "secret"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckBoot(mock *robotmock.Mock, want robotmock.Boot) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		got := mock.Boot(testAccServerNumber)
+		got.Password = ""
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return fmt.Errorf("expected boot configuration %+v, got %+v", want, got)
+		}
+		return nil
+	}
+}
+
+func TestResourceBootCreate(t *testing.T) {
+	mock, client := newBootTestClient(t)
+
+	d := schema.TestResourceDataRaw(t, resourceBoot().Schema, map[string]any{
+		"server_id":        1001,
+		"active_profile":   "rescue",
+		"architecture":     "64",
+		"operating_system": "linux",
+	})
+	if diags := resourceBootCreate(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	// a new resource has no ID yet, the server comes from server_id
+	if d.Id() != "1001" {
+		t.Fatalf("Expected ID 1001, got %q", d.Id())
+	}
+	if boot := mock.Boot(1001); boot.Type != "rescue" || boot.OS != "linux" {
+		t.Fatalf("Unexpected boot configuration %+v", boot)
+	}
+}
+
+func TestResourceBootImportState(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		boot    robotmock.Boot
+		profile string
+	}{
+		{
+			name:    "server number",
+			id:      "1001",
+			boot:    robotmock.Boot{Type: "rescue", OS: "linux", Arch: 64},
+			profile: "rescue",
+		},
+		{
+			name:    "server IP",
+			id:      "198.51.100.1",
+			boot:    robotmock.Boot{Type: "rescue", OS: "linux", Arch: 64},
+			profile: "rescue",
+		},
+		{
+			name: "nothing active",
+			id:   "198.51.100.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, client := newBootTestClient(t)
+			mock.SetBoot(1001, tt.boot)
+
+			d := resourceBoot().Data(nil)
+			d.SetId(tt.id)
+			results, err := resourceBootImportState(context.Background(), d, client)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if results[0].Id() != tt.id {
+				t.Fatalf("Expected ID %s, got %s", tt.id, results[0].Id())
+			}
+			if serverID, ok := results[0].Get("server_id").(int); !ok || serverID != 1001 {
+				t.Fatalf("Expected server_id 1001, got %#v", results[0].Get("server_id"))
+			}
+			if profile := results[0].Get("active_profile"); profile != tt.profile {
+				t.Fatalf("Expected active_profile %q, got %v", tt.profile, profile)
+			}
+		})
+	}
+}
+
+func TestResourceBootReadImportedByIP(t *testing.T) {
+	mock, client := newBootTestClient(t)
+	mock.SetBoot(1001, robotmock.Boot{Type: "rescue", OS: "linux", Arch: 64})
+
+	// states upgraded from configurations imported by server IP
+	d := resourceBoot().Data(nil)
+	d.SetId("198.51.100.1")
+	if diags := resourceBootRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	if serverID, ok := d.Get("server_id").(int); !ok || serverID != 1001 {
+		t.Fatalf("Expected server_id 1001, got %#v", d.Get("server_id"))
+	}
+}
+
+func TestResourceBootUpdate(t *testing.T) {
+	mock, client := newBootTestClient(t)
+	mock.SetBoot(1001, robotmock.Boot{Type: "rescue", OS: "linux", Arch: 64})
+
+	r := resourceBoot()
+	state := &terraform.InstanceState{
+		ID: "1001",
+		Attributes: map[string]string{
+			"id":               "1001",
+			"server_id":        "1001",
+			"active_profile":   "rescue",
+			"architecture":     "64",
+			"operating_system": "linux",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]any{
+		"server_id":        1001,
+		"active_profile":   "linux",
+		"architecture":     "64",
+		"operating_system": "Debian 12 base",
+		"language":         "en",
+	})
+	diff, err := r.Diff(context.Background(), state, config, client)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Robot rejects activating linux while rescue is still active
+	if _, diags := r.Apply(context.Background(), state, diff, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if boot := mock.Boot(1001); boot.Type != "linux" || boot.OS != "Debian 12 base" || boot.Lang != "en" {
+		t.Fatalf("Unexpected boot configuration %+v", boot)
+	}
+}
+
+func TestResourceBootUpdateDeactivated(t *testing.T) {
+	mock := robotmock.New(robotmock.Config{Username: "user", Password: "pass"})
+	mock.AddServer(robotmock.Server{Number: 1001, IP: "198.51.100.1"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		mock.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	// the rescue system in state has been booted, so Robot deactivated it
+	r := resourceBoot()
+	state := &terraform.InstanceState{
+		ID: "1001",
+		Attributes: map[string]string{
+			"id":               "1001",
+			"server_id":        "1001",
+			"active_profile":   "rescue",
+			"architecture":     "64",
+			"operating_system": "linux",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]any{
+		"server_id":        1001,
+		"active_profile":   "linux",
+		"architecture":     "64",
+		"operating_system": "Debian 12 base",
+		"language":         "en",
+	})
+	diff, err := r.Diff(context.Background(), state, config, client)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, diags := r.Apply(context.Background(), state, diff, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if boot := mock.Boot(1001); boot.Type != "linux" || boot.OS != "Debian 12 base" || boot.Lang != "en" {
+		t.Fatalf("Unexpected boot configuration %+v", boot)
+	}
+}
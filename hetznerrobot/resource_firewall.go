@@ -3,334 +3,428 @@ package hetznerrobot
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+)
+
+// firewallDefaultTimeout is the create and update timeout unless the
+// timeouts block sets another one.
+const firewallDefaultTimeout = 5 * time.Minute
+
+// firewallResource was an SDK resource before. Its schema keeps the types of
+// the SDK schema, so states written by either can be read by the other.
+// Optional rule fields are computed with an empty default, as the SDK did not
+// distinguish empty from unset strings.
+type firewallResource struct {
+	client HetznerRobotClient
+}
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+var (
+	_ resource.ResourceWithConfigure      = (*firewallResource)(nil)
+	_ resource.ResourceWithImportState    = (*firewallResource)(nil)
+	_ resource.ResourceWithUpgradeState   = (*firewallResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*firewallResource)(nil)
 )
 
-func resourceFirewall() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceFirewallCreate,
-		ReadContext:   resourceFirewallRead,
-		UpdateContext: resourceFirewallUpdate,
-		DeleteContext: resourceFirewallDelete,
-		Description:   "Manages firewall configuration for a Hetzner Robot server",
-		Importer: &schema.ResourceImporter{
-			StateContext: resourceFirewallImportState,
-		},
-		Schema: map[string]*schema.Schema{
-			"server_ip": {
-				Type:     schema.TypeString,
-				Required: true,
+func newFirewallResource() resource.Resource {
+	return &firewallResource{}
+}
+
+type firewallResourceModel struct {
+	ID           types.String           `tfsdk:"id"`
+	ServerIP     types.String           `tfsdk:"server_ip"`
+	Active       types.Bool             `tfsdk:"active"`
+	WhitelistHOS types.Bool             `tfsdk:"whitelist_hos"`
+	FilterIPv6   types.Bool             `tfsdk:"filter_ipv6"`
+	Port         types.String           `tfsdk:"port"`
+	Rules        []firewallRuleModel    `tfsdk:"rule"`
+	Timeouts     *firewallTimeoutsModel `tfsdk:"timeouts"`
+}
+
+type firewallRuleModel struct {
+	Name      types.String `tfsdk:"name"`
+	DstIP     types.String `tfsdk:"dst_ip"`
+	DstPort   types.String `tfsdk:"dst_port"`
+	SrcIP     types.String `tfsdk:"src_ip"`
+	SrcPort   types.String `tfsdk:"src_port"`
+	Protocol  types.String `tfsdk:"protocol"`
+	TCPFlags  types.String `tfsdk:"tcp_flags"`
+	Action    types.String `tfsdk:"action"`
+	IPVersion types.String `tfsdk:"ip_version"`
+}
+
+type firewallTimeoutsModel struct {
+	Create types.String `tfsdk:"create"`
+	Update types.String `tfsdk:"update"`
+}
+
+func (r *firewallResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall"
+}
+
+func (r *firewallResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	ruleField := func(field string, validators ...validator.String) schema.StringAttribute {
+		return schema.StringAttribute{
+			Optional:      true,
+			Computed:      true,
+			Default:       stringdefault.StaticString(""),
+			Validators:    validators,
+			PlanModifiers: []planmodifier.String{canonicalFirewallRuleFieldModifier{field: field}},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Version:     1,
+		Description: "Manages firewall configuration for a Hetzner Robot server",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			"active": {
-				Type:     schema.TypeBool,
+			"server_ip": schema.StringAttribute{
 				Required: true,
 			},
-			"whitelist_hos": {
-				Type:     schema.TypeBool,
+			"active": schema.BoolAttribute{
 				Required: true,
 			},
-			"rule": {
-				Type:     schema.TypeList,
+			"whitelist_hos": schema.BoolAttribute{
 				Required: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"dst_ip": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"dst_port": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"src_ip": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"src_port": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"protocol": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"tcp_flags": {
-							Type:     schema.TypeString,
+			},
+			"filter_ipv6": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether the rules are also applied to IPv6 traffic",
+			},
+			"port": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(firewallPortMain),
+				Description: "Switch port the firewall applies to (\"main\" or \"kvm\")",
+				Validators:  []validator.String{firewallOneOfValidator(firewallPortMain, firewallPortKVM)},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"rule": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
 							Optional: true,
+							Computed: true,
+							Default:  stringdefault.StaticString(""),
 						},
-						"action": {
-							Type: schema.TypeString,
-							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
-								"accept",
-								"discard",
-							}, false)),
-							Required: true,
+						"dst_ip":    ruleField("dst_ip", firewallIPValidator),
+						"dst_port":  ruleField("dst_port", firewallPortValidator),
+						"src_ip":    ruleField("src_ip", firewallIPValidator),
+						"src_port":  ruleField("src_port", firewallPortValidator),
+						"protocol":  ruleField("protocol", firewallProtocolValidator),
+						"tcp_flags": ruleField("tcp_flags", firewallTCPFlagsValidator),
+						"action": schema.StringAttribute{
+							Required:   true,
+							Validators: []validator.String{firewallOneOfValidator("accept", "discard")},
 						},
-						"ip_version": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "ipv4",
-							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
-								"ipv4",
-								"ipv6",
-							}, false)),
+						"ip_version": schema.StringAttribute{
+							Optional:      true,
+							Computed:      true,
+							Default:       stringdefault.StaticString("ipv4"),
+							Validators:    []validator.String{firewallOneOfValidator("ipv4", "ipv6")},
+							PlanModifiers: []planmodifier.String{canonicalFirewallRuleFieldModifier{field: "ip_version"}},
 						},
 					},
 				},
 			},
+			"timeouts": schema.SingleNestedBlock{
+				Attributes: map[string]schema.Attribute{
+					"create": schema.StringAttribute{
+						Optional:    true,
+						Description: "How long to wait for Robot to apply the firewall, e.g. \"10m\". Defaults to 5m",
+						Validators:  []validator.String{firewallTimeoutValidator},
+					},
+					"update": schema.StringAttribute{
+						Optional:    true,
+						Description: "How long to wait for Robot to apply changes, e.g. \"10m\". Defaults to 5m",
+						Validators:  []validator.String{firewallTimeoutValidator},
+					},
+				},
+			},
 		},
 	}
 }
 
-func resourceFirewallImportState(ctx context.Context, d *schema.ResourceData, m any) ([]*schema.ResourceData, error) {
-	c, ok := m.(HetznerRobotClient)
+func (r *firewallResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil until the provider is configured
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(HetznerRobotClient)
 	if !ok {
-		return nil, fmt.Errorf("unable to cast meta to HetznerRobotClient")
+		resp.Diagnostics.AddError("Unexpected provider data", "Unable to cast provider data to HetznerRobotClient")
+		return
 	}
+	r.client = client
+}
 
-	firewallID := d.Id()
+func (r *firewallResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var rules []firewallRuleModel
+	if diags := req.Config.GetAttribute(ctx, path.Root("rule"), &rules); diags.HasError() {
+		// rules from dynamic blocks are unknown until their for_each is
+		return
+	}
 
-	firewall, err := c.getFirewall(ctx, firewallID)
-	if err != nil {
-		return nil, fmt.Errorf("could not find firewall with ID %s: %w", firewallID, err)
-	}
-
-	active := firewall.Status == "active"
-
-	rules := make([]map[string]any, 0)
-	for _, rule := range firewall.Rules.Input {
-		r := map[string]any{
-			"name":       rule.Name,
-			"src_ip":     rule.SrcIP,
-			"src_port":   rule.SrcPort,
-			"dst_ip":     rule.DstIP,
-			"dst_port":   rule.DstPort,
-			"protocol":   rule.Protocol,
-			"tcp_flags":  rule.TCPFlags,
-			"action":     rule.Action,
-			"ip_version": rule.IPVersion,
-		}
-		rules = append(rules, r)
+	// rule was a required block of the SDK resource
+	if len(rules) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("rule"), "Missing rule block", "At least one rule block is required.")
+		return
 	}
 
-	_ = d.Set("active", active)
-	_ = d.Set("rule", rules)
-	_ = d.Set("server_ip", firewall.IP)
-	_ = d.Set("whitelist_hos", firewall.WhitelistHetznerServices)
-	d.SetId(firewall.IP)
+	robotRules := make([]HetznerRobotFirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		if !rule.isKnown() {
+			// checked again once the values are known
+			return
+		}
+		robotRules = append(robotRules, rule.rule())
+	}
+	if err := checkFirewallRules(robotRules); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("rule"), "Invalid firewall rules", err.Error())
+	}
+}
 
-	results := make([]*schema.ResourceData, 1)
-	results[0] = d
-	return results, nil
+func (r *firewallResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-func resourceFirewallCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	c, ok := m.(HetznerRobotClient)
-	if !ok {
-		return diag.Errorf("Unable to cast meta to HetznerRobotClient")
+func (r *firewallResource) UpgradeState(context.Context) map[int64]resource.StateUpgrader {
+	schemaV0 := resourceFirewallSchemaV0()
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &schemaV0,
+			StateUpgrader: resourceFirewallStateUpgradeV0,
+		},
 	}
+}
 
-	serverIP, _ := d.Get("server_ip").(string)
+func (r *firewallResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan firewallResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	status := "disabled"
-	if active, _ := d.Get("active").(bool); active {
-		status = "active"
+	serverIP := plan.ServerIP.ValueString()
+	firewall := plan.firewall()
+	applied, err := r.client.setFirewall(ctx, firewall)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to set firewall for %s", serverIP), err.Error())
+		return
 	}
 
-	var diags diag.Diagnostics
-	rules := make([]HetznerRobotFirewallRule, 0)
-	rules_data, _ := d.Get("rule").([]any)
-	for _, ruleMap := range rules_data {
-		ruleProperties, ok := ruleMap.(map[string]any)
-		if !ok {
-			continue
-		}
-		ipVersion := "ipv4"
-		if v, ok := ruleProperties["ip_version"].(string); ok && v != "" {
-			ipVersion = v
-		}
-		name, _ := ruleProperties["name"].(string)
-		srcIP, _ := ruleProperties["src_ip"].(string)
-		srcPort, _ := ruleProperties["src_port"].(string)
-		dstIP, _ := ruleProperties["dst_ip"].(string)
-		dstPort, _ := ruleProperties["dst_port"].(string)
-		protocol, _ := ruleProperties["protocol"].(string)
-		tcpFlags, _ := ruleProperties["tcp_flags"].(string)
-		action, _ := ruleProperties["action"].(string)
-
-		// Warn about IPv6 restrictions
-		if ipVersion == "ipv6" {
-			if srcIP != "" {
-				diags = append(diags, diag.Diagnostic{
-					Severity: diag.Warning,
-					Summary:  fmt.Sprintf("IPv6 rule '%s': src_ip field ignored", name),
-					Detail:   "Hetzner Robot API does not support source IP filtering for IPv6 rules. The src_ip field will be ignored.",
-				})
-			}
-			if dstIP != "" {
-				diags = append(diags, diag.Diagnostic{
-					Severity: diag.Warning,
-					Summary:  fmt.Sprintf("IPv6 rule '%s': dst_ip field ignored", name),
-					Detail:   "Hetzner Robot API does not support destination IP filtering for IPv6 rules. The dst_ip field will be ignored.",
-				})
-			}
-		}
+	// The firewall is saved even if it does not settle, so the next apply
+	// updates it instead of creating it again
+	plan.ID = types.StringValue(serverIP)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 
-		rules = append(rules, HetznerRobotFirewallRule{
-			Name:      name,
-			SrcIP:     srcIP,
-			SrcPort:   srcPort,
-			DstIP:     dstIP,
-			DstPort:   dstPort,
-			Protocol:  protocol,
-			TCPFlags:  tcpFlags,
-			Action:    action,
-			IPVersion: ipVersion,
-		})
+	if err := waitForFirewall(ctx, r.client, serverIP, applied, firewall.Status, plan.Timeouts.create()); err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Firewall for %s did not settle", serverIP), err.Error())
+	}
+}
+
+func (r *firewallResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state firewallResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if err := c.setFirewall(ctx, HetznerRobotFirewall{
-		IP:                       serverIP,
-		WhitelistHetznerServices: func() bool { val, _ := d.Get("whitelist_hos").(bool); return val }(),
-		Status:                   status,
-		Rules:                    HetznerRobotFirewallRules{Input: rules},
-	}); err != nil {
-		return diag.FromErr(err)
+	firewall, err := r.client.getFirewall(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to read firewall for %s", state.ID.ValueString()), err.Error())
+		return
 	}
 
-	d.SetId(serverIP)
+	state.ServerIP = types.StringValue(firewall.IP)
+	state.Active = types.BoolValue(firewall.Status == firewallStatusActive)
+	state.WhitelistHOS = types.BoolValue(firewall.WhitelistHetznerServices)
+	state.FilterIPv6 = types.BoolValue(firewall.FilterIPv6)
+	if firewall.Port != "" {
+		state.Port = types.StringValue(firewall.Port)
+	} else if state.Port.IsNull() {
+		state.Port = types.StringValue(firewallPortMain)
+	}
+	state.Rules = firewallRuleModels(canonicalFirewallRules(firewall.Rules.Input))
 
-	return diags
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-func resourceFirewallRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	c, ok := m.(HetznerRobotClient)
-	if !ok {
-		return diag.Errorf("Unable to cast meta to HetznerRobotClient")
+func (r *firewallResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan firewallResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	serverIP := d.Id()
+	serverIP := plan.ServerIP.ValueString()
+	timeout := plan.Timeouts.update()
 
-	firewall, err := c.getFirewall(ctx, serverIP)
+	// Robot rejects changes while a previous one is still being applied
+	if err := waitForFirewall(ctx, r.client, serverIP, nil, "", timeout); err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Firewall for %s is not ready for changes", serverIP), err.Error())
+		return
+	}
+
+	firewall := plan.firewall()
+	applied, err := r.client.setFirewall(ctx, firewall)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	active := firewall.Status == "active"
-
-	rules := make([]map[string]any, 0)
-	for _, rule := range firewall.Rules.Input {
-		r := map[string]any{
-			"name":       rule.Name,
-			"src_ip":     rule.SrcIP,
-			"src_port":   rule.SrcPort,
-			"dst_ip":     rule.DstIP,
-			"dst_port":   rule.DstPort,
-			"protocol":   rule.Protocol,
-			"tcp_flags":  rule.TCPFlags,
-			"action":     rule.Action,
-			"ip_version": rule.IPVersion,
-		}
-		rules = append(rules, r)
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to set firewall for %s", serverIP), err.Error())
+		return
 	}
-	_ = d.Set("active", active)
-	_ = d.Set("rule", rules)
-	_ = d.Set("server_ip", firewall.IP)
-	_ = d.Set("whitelist_hos", firewall.WhitelistHetznerServices)
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 
-	return diags
+	if err := waitForFirewall(ctx, r.client, serverIP, applied, firewall.Status, timeout); err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Firewall for %s did not settle", serverIP), err.Error())
+	}
 }
 
-func resourceFirewallUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	c, ok := m.(HetznerRobotClient)
-	if !ok {
-		return diag.Errorf("Unable to cast meta to HetznerRobotClient")
+// Delete only removes the firewall from the state, Robot keeps it as it is.
+func (r *firewallResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {
+}
+
+// firewall returns the firewall to send to Robot.
+func (m firewallResourceModel) firewall() HetznerRobotFirewall {
+	status := firewallStatusDisabled
+	if m.Active.ValueBool() {
+		status = firewallStatusActive
 	}
 
-	serverIP, _ := d.Get("server_ip").(string)
+	rules := make([]HetznerRobotFirewallRule, 0, len(m.Rules))
+	for _, rule := range m.Rules {
+		rules = append(rules, rule.rule())
+	}
 
-	status := "disabled"
-	if active, _ := d.Get("active").(bool); active {
-		status = "active"
+	return HetznerRobotFirewall{
+		IP:                       m.ServerIP.ValueString(),
+		WhitelistHetznerServices: m.WhitelistHOS.ValueBool(),
+		Status:                   status,
+		FilterIPv6:               m.FilterIPv6.ValueBool(),
+		Port:                     m.Port.ValueString(),
+		Rules:                    HetznerRobotFirewallRules{Input: rules},
 	}
+}
 
-	var diags diag.Diagnostics
-	rules := make([]HetznerRobotFirewallRule, 0)
-	rules_data, _ := d.Get("rule").([]any)
-	for _, ruleMap := range rules_data {
-		ruleProperties, ok := ruleMap.(map[string]any)
-		if !ok {
-			continue
-		}
-		ipVersion := "ipv4"
-		if v, ok := ruleProperties["ip_version"].(string); ok && v != "" {
-			ipVersion = v
-		}
-		name, _ := ruleProperties["name"].(string)
-		srcIP, _ := ruleProperties["src_ip"].(string)
-		srcPort, _ := ruleProperties["src_port"].(string)
-		dstIP, _ := ruleProperties["dst_ip"].(string)
-		dstPort, _ := ruleProperties["dst_port"].(string)
-		protocol, _ := ruleProperties["protocol"].(string)
-		tcpFlags, _ := ruleProperties["tcp_flags"].(string)
-		action, _ := ruleProperties["action"].(string)
-
-		// Warn about IPv6 restrictions
-		if ipVersion == "ipv6" {
-			if srcIP != "" {
-				diags = append(diags, diag.Diagnostic{
-					Severity: diag.Warning,
-					Summary:  fmt.Sprintf("IPv6 rule '%s': src_ip field ignored", name),
-					Detail:   "Hetzner Robot API does not support source IP filtering for IPv6 rules. The src_ip field will be ignored.",
-				})
-			}
-			if dstIP != "" {
-				diags = append(diags, diag.Diagnostic{
-					Severity: diag.Warning,
-					Summary:  fmt.Sprintf("IPv6 rule '%s': dst_ip field ignored", name),
-					Detail:   "Hetzner Robot API does not support destination IP filtering for IPv6 rules. The dst_ip field will be ignored.",
-				})
-			}
+func (m firewallRuleModel) rule() HetznerRobotFirewallRule {
+	ipVersion := m.IPVersion.ValueString()
+	if ipVersion == "" {
+		ipVersion = "ipv4"
+	}
+	return HetznerRobotFirewallRule{
+		Name:      m.Name.ValueString(),
+		SrcIP:     m.SrcIP.ValueString(),
+		SrcPort:   m.SrcPort.ValueString(),
+		DstIP:     m.DstIP.ValueString(),
+		DstPort:   m.DstPort.ValueString(),
+		Protocol:  m.Protocol.ValueString(),
+		TCPFlags:  m.TCPFlags.ValueString(),
+		Action:    m.Action.ValueString(),
+		IPVersion: ipVersion,
+	}
+}
+
+func (m firewallRuleModel) isKnown() bool {
+	for _, value := range []types.String{m.Name, m.DstIP, m.DstPort, m.SrcIP, m.SrcPort, m.Protocol, m.TCPFlags, m.Action, m.IPVersion} {
+		if value.IsUnknown() {
+			return false
 		}
+	}
+	return true
+}
 
-		rules = append(rules, HetznerRobotFirewallRule{
-			Name:      name,
-			SrcIP:     srcIP,
-			SrcPort:   srcPort,
-			DstIP:     dstIP,
-			DstPort:   dstPort,
-			Protocol:  protocol,
-			TCPFlags:  tcpFlags,
-			Action:    action,
-			IPVersion: ipVersion,
+func firewallRuleModels(rules []HetznerRobotFirewallRule) []firewallRuleModel {
+	models := make([]firewallRuleModel, 0, len(rules))
+	for _, rule := range rules {
+		models = append(models, firewallRuleModel{
+			Name:      types.StringValue(rule.Name),
+			DstIP:     types.StringValue(rule.DstIP),
+			DstPort:   types.StringValue(rule.DstPort),
+			SrcIP:     types.StringValue(rule.SrcIP),
+			SrcPort:   types.StringValue(rule.SrcPort),
+			Protocol:  types.StringValue(rule.Protocol),
+			TCPFlags:  types.StringValue(rule.TCPFlags),
+			Action:    types.StringValue(rule.Action),
+			IPVersion: types.StringValue(rule.IPVersion),
 		})
 	}
+	return models
+}
 
-	if err := c.setFirewall(ctx, HetznerRobotFirewall{
-		IP:                       serverIP,
-		WhitelistHetznerServices: func() bool { val, _ := d.Get("whitelist_hos").(bool); return val }(),
-		Status:                   status,
-		Rules:                    HetznerRobotFirewallRules{Input: rules},
-	}); err != nil {
-		return diag.FromErr(err)
+func (t *firewallTimeoutsModel) create() time.Duration {
+	if t == nil {
+		return firewallDefaultTimeout
+	}
+	return firewallTimeout(t.Create)
+}
+
+func (t *firewallTimeoutsModel) update() time.Duration {
+	if t == nil {
+		return firewallDefaultTimeout
 	}
+	return firewallTimeout(t.Update)
+}
 
-	return diags
+// firewallTimeout parses a timeout validated by firewallTimeoutValidator.
+func firewallTimeout(value types.String) time.Duration {
+	if timeout, err := time.ParseDuration(value.ValueString()); err == nil {
+		return timeout
+	}
+	return firewallDefaultTimeout
 }
 
-func resourceFirewallDelete(_ context.Context, _ *schema.ResourceData, _ any) diag.Diagnostics {
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+// waitForFirewall polls the firewall of serverIP until Robot has finished
+// applying the last change. Polling starts from firewall if it is not nil,
+// e.g. the firewall Robot returned for the change, which already reports
+// the change as "in process". If status is not empty, the settled firewall
+// must also report that status.
+func waitForFirewall(ctx context.Context, c HetznerRobotClient, serverIP string, firewall *HetznerRobotFirewall, status string, timeout time.Duration) error {
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{firewallStatusInProcess},
+		Target:  []string{firewallStatusActive, firewallStatusDisabled},
+		Refresh: func() (any, string, error) {
+			if firewall != nil {
+				current := firewall
+				firewall = nil
+				return current, current.Status, nil
+			}
+			current, err := c.getFirewall(withoutResponseCache(ctx), serverIP)
+			if err != nil {
+				return nil, "", err
+			}
+			return current, current.Status, nil
+		},
+		Timeout:      timeout,
+		PollInterval: firewallPollInterval,
+	}
 
-	return diags
+	result, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return fmt.Errorf("waiting for firewall to leave %q: %w", firewallStatusInProcess, err)
+	}
+
+	settled, ok := result.(*HetznerRobotFirewall)
+	if !ok {
+		return fmt.Errorf("unexpected firewall result type %T", result)
+	}
+	if status != "" && settled.Status != status {
+		return fmt.Errorf("firewall settled with status %q, expected %q", settled.Status, status)
+	}
+	return nil
 }
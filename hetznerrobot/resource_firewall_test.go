@@ -0,0 +1,504 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/robotmock"
+)
+
+// setFirewallPollInterval shortens firewallPollInterval for the duration
+// of the test.
+func setFirewallPollInterval(t *testing.T, interval time.Duration) {
+	t.Helper()
+	previous := firewallPollInterval
+	firewallPollInterval = interval
+	t.Cleanup(func() { firewallPollInterval = previous })
+}
+
+func TestWaitForFirewall(t *testing.T) {
+	setFirewallPollInterval(t, 10*time.Millisecond)
+
+	tests := []struct {
+		name      string
+		statuses  []string
+		want      string
+		expectErr string
+	}{
+		{
+			name:     "settles active",
+			statuses: []string{"in process", "in process", "active"},
+			want:     "active",
+		},
+		{
+			name:     "settles disabled",
+			statuses: []string{"in process", "disabled"},
+			want:     "disabled",
+		},
+		{
+			name:     "any settled status",
+			statuses: []string{"active"},
+			want:     "",
+		},
+		{
+			name:      "settles with wrong status",
+			statuses:  []string{"in process", "disabled"},
+			want:      "active",
+			expectErr: `firewall settled with status "disabled", expected "active"`,
+		},
+		{
+			name:      "error state",
+			statuses:  []string{"in process", "failed"},
+			want:      "active",
+			expectErr: "unexpected state 'failed'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				idx := int(calls.Add(1)) - 1
+				if idx >= len(tt.statuses) {
+					idx = len(tt.statuses) - 1
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"firewall": {"server_ip": "1.2.3.4", "status": %q, "rules": {"input": []}}}`, tt.statuses[idx])
+			}))
+			defer server.Close()
+
+			client := NewHetznerRobotClient("user", "pass", server.URL)
+			err := waitForFirewall(context.Background(), client, "1.2.3.4", nil, tt.want, time.Second)
+
+			if tt.expectErr == "" && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.expectErr != "" {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				if !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("Expected error containing '%s', got: %v", tt.expectErr, err)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForFirewallAfterChange(t *testing.T) {
+	setFirewallPollInterval(t, 10*time.Millisecond)
+
+	tests := []struct {
+		name      string
+		posted    string
+		polled    []string
+		wantPolls int32
+	}{
+		{
+			name:      "polls while in process",
+			posted:    "in process",
+			polled:    []string{"in process", "active"},
+			wantPolls: 2,
+		},
+		{
+			name:      "settled right away",
+			posted:    "active",
+			wantPolls: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.posted
+				if r.Method == http.MethodGet {
+					idx := min(int(polls.Add(1))-1, len(tt.polled)-1)
+					status = tt.polled[idx]
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"firewall": {"server_ip": "1.2.3.4", "status": %q, "rules": {"input": []}}}`, status)
+			}))
+			defer server.Close()
+
+			client := NewHetznerRobotClient("user", "pass", server.URL)
+			firewall, err := client.setFirewall(context.Background(), HetznerRobotFirewall{IP: "1.2.3.4", Status: "active"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if firewall.Status != tt.posted {
+				t.Fatalf("Expected status %q from setFirewall, got %q", tt.posted, firewall.Status)
+			}
+
+			if err := waitForFirewall(context.Background(), client, "1.2.3.4", firewall, "active", time.Second); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := polls.Load(); got != tt.wantPolls {
+				t.Fatalf("Expected %d polls, got %d", tt.wantPolls, got)
+			}
+		})
+	}
+}
+
+func TestWaitForFirewallTimeout(t *testing.T) {
+	setFirewallPollInterval(t, 10*time.Millisecond)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"firewall": {"server_ip": "1.2.3.4", "status": "in process", "rules": {"input": []}}}`))
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	err := waitForFirewall(context.Background(), client, "1.2.3.4", nil, "active", 100*time.Millisecond)
+	if err == nil {
+		t.Fatal("Expected timeout error but got none")
+	}
+	if !strings.Contains(err.Error(), "timeout while waiting for state") {
+		t.Fatalf("Expected timeout error, got: %v", err)
+	}
+}
+
+func TestAccResourceFirewall(t *testing.T) {
+	mock, providerConfig := testAccRobotMock(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "hetznerrobot_firewall" "test" {
+  server_ip     = %q
+  active        = true
+  whitelist_hos = true
+
+  rule {
+    name     = "ssh"
+    src_ip   = "10.0.0.0/8"
+    dst_port = "22"
+    protocol = "tcp"
+    action   = "accept"
+  }
+
+  rule {
+    name     = "https"
+    dst_port = "443"
+    protocol = "tcp"
+    action   = "accept"
+  }
+}
+`, testAccServerIP),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetznerrobot_firewall.test", "id", testAccServerIP),
+					resource.TestCheckResourceAttr("hetznerrobot_firewall.test", "rule.#", "2"),
+					resource.TestCheckResourceAttr("hetznerrobot_firewall.test", "rule.0.src_ip", "10.0.0.0/8"),
+					testAccCheckFirewall(mock, "active", "ssh", "https"),
+				),
+			},
+			{
+				ResourceName:      "hetznerrobot_firewall.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "hetznerrobot_firewall" "test" {
+  server_ip     = %q
+  active        = false
+  whitelist_hos = false
+  filter_ipv6   = true
+
+  rule {
+    name       = "ssh-v6"
+    dst_port   = "22"
+    protocol   = "tcp"
+    action     = "accept"
+    ip_version = "ipv6"
+  }
+}
+`, testAccServerIP),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetznerrobot_firewall.test", "active", "false"),
+					resource.TestCheckResourceAttr("hetznerrobot_firewall.test", "filter_ipv6", "true"),
+					resource.TestCheckResourceAttr("hetznerrobot_firewall.test", "rule.#", "1"),
+					testAccCheckFirewall(mock, "disabled", "ssh-v6"),
+				),
+			},
+			{
+				ResourceName:      "hetznerrobot_firewall.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccCheckFirewall checks the firewall the fake applied to the test
+// server, by status and input rule names.
+func testAccCheckFirewall(mock *robotmock.Mock, status string, ruleNames ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		firewall := mock.Firewall(testAccServerNumber)
+		if firewall.Status != status {
+			return fmt.Errorf("expected firewall status %q, got %q", status, firewall.Status)
+		}
+		names := make([]string, 0, len(firewall.Input))
+		for _, rule := range firewall.Input {
+			names = append(names, rule.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(ruleNames, ",") {
+			return fmt.Errorf("expected input rules %v, got %v", ruleNames, names)
+		}
+		return nil
+	}
+}
+
+// firewallSDKState is the state the SDK version of the resource wrote for
+// the firewall TestResourceFirewallSDKState sets up.
+const firewallSDKState = `{
+  "active": true,
+  "filter_ipv6": false,
+  "id": "198.51.100.1",
+  "port": "main",
+  "rule": [
+    {"action": "accept", "dst_ip": "", "dst_port": "22", "ip_version": "ipv4", "name": "ssh", "protocol": "tcp", "src_ip": "10.0.0.0/8", "src_port": "", "tcp_flags": ""},
+    {"action": "accept", "dst_ip": "198.51.100.1/32", "dst_port": "443", "ip_version": "ipv4", "name": "https", "protocol": "tcp", "src_ip": "", "src_port": "", "tcp_flags": ""},
+    {"action": "accept", "dst_ip": "", "dst_port": "22", "ip_version": "ipv6", "name": "ssh-v6", "protocol": "tcp", "src_ip": "", "src_port": "", "tcp_flags": ""}
+  ],
+  "server_ip": "198.51.100.1",
+  "timeouts": null,
+  "whitelist_hos": true
+}`
+
+// TestResourceFirewallSDKState reads and plans a state written by the SDK
+// version of the resource, which must not show any changes.
+func TestResourceFirewallSDKState(t *testing.T) {
+	ctx := context.Background()
+	mock := robotmock.New(robotmock.Config{Username: "acc-user", Password: "acc-pass"})
+	mock.AddServer(robotmock.Server{Number: testAccServerNumber, IP: testAccServerIP})
+	// the firewall the state was read from, as Robot returns it
+	mock.SetFirewall(testAccServerNumber, robotmock.Firewall{
+		Status:       "active",
+		WhitelistHOS: true,
+		Port:         "main",
+		Input: []robotmock.FirewallRule{
+			{IPVersion: "ipv4", Name: "ssh", SrcIP: "10.0.0.0/8", DstPort: "22-22", Protocol: "TCP", Action: "ACCEPT"},
+			{IPVersion: "ipv4", Name: "https", DstIP: "198.51.100.1/32", DstPort: "443", Protocol: "tcp", Action: "accept"},
+			{IPVersion: "ipv6", Name: "ssh-v6", DstPort: "22", Protocol: "tcp", Action: "accept"},
+		},
+	})
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+
+	providerServer := testFirewallProviderServer(t, server.URL)
+	schemas, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	firewallType := schemas.ResourceSchemas["hetznerrobot_firewall"].ValueType()
+
+	upgradeResp, err := providerServer.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "hetznerrobot_firewall",
+		Version:  0,
+		RawState: &tfprotov6.RawState{JSON: []byte(firewallSDKState)},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, diagnostic := range upgradeResp.Diagnostics {
+		t.Fatalf("Unexpected diagnostic: %s: %s", diagnostic.Summary, diagnostic.Detail)
+	}
+
+	readResp, err := providerServer.ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+		TypeName:     "hetznerrobot_firewall",
+		CurrentState: upgradeResp.UpgradedState,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, diagnostic := range readResp.Diagnostics {
+		t.Fatalf("Unexpected diagnostic: %s: %s", diagnostic.Summary, diagnostic.Detail)
+	}
+	prior := testDynamicValueValue(t, firewallType, readResp.NewState)
+	if upgraded := testDynamicValueValue(t, firewallType, upgradeResp.UpgradedState); !prior.Equal(upgraded) {
+		t.Fatalf("Expected Read to keep the state, got %v", prior)
+	}
+
+	// the configuration the state was applied with, partly in other forms
+	// Robot treats as equal
+	config := `{
+  "server_ip": "198.51.100.1",
+  "active": true,
+  "whitelist_hos": true,
+  "rule": [
+    {"name": "ssh", "src_ip": "10.0.0.0/8", "src_port": "0-65535", "dst_port": "%s", "protocol": "tcp", "action": "accept"},
+    {"name": "https", "dst_ip": "198.51.100.1", "dst_port": "443-443", "protocol": "tcp", "action": "accept"},
+    {"name": "ssh-v6", "dst_port": "22", "protocol": "tcp", "action": "accept", "ip_version": "ipv6"}
+  ]
+}`
+	plan := func(t *testing.T, dstPort string) tftypes.Value {
+		t.Helper()
+		resp, err := providerServer.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+			TypeName:         "hetznerrobot_firewall",
+			PriorState:       readResp.NewState,
+			ProposedNewState: testDynamicValue(t, firewallType, fmt.Sprintf(config, dstPort)),
+			Config:           testDynamicValue(t, firewallType, fmt.Sprintf(config, dstPort)),
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, diagnostic := range resp.Diagnostics {
+			t.Fatalf("Unexpected diagnostic: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+		if len(resp.RequiresReplace) != 0 {
+			t.Fatalf("Expected no replacement, got %v", resp.RequiresReplace)
+		}
+		return testDynamicValueValue(t, firewallType, resp.PlannedState)
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		if planned := plan(t, "22-22"); !planned.Equal(prior) {
+			diffs, _ := planned.Diff(prior)
+			for _, diff := range diffs {
+				t.Errorf("%s: planned %v, prior %v", diff.Path, diff.Value1, diff.Value2)
+			}
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		diffs, err := plan(t, "2222").Diff(prior)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want := tftypes.NewAttributePath().WithAttributeName("rule").WithElementKeyInt(0).WithAttributeName("dst_port")
+		if len(diffs) != 1 || !diffs[0].Path.Equal(want) {
+			t.Fatalf("Expected only %s to change, got %v", want, diffs)
+		}
+	})
+}
+
+func TestResourceFirewallValidateConfig(t *testing.T) {
+	providerServer := testFirewallProviderServer(t, "http://127.0.0.1")
+	schemas, err := providerServer.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	firewallType := schemas.ResourceSchemas["hetznerrobot_firewall"].ValueType()
+
+	tests := []struct {
+		name      string
+		rules     string
+		expectErr string
+	}{
+		{
+			name:  "valid",
+			rules: `[{"dst_port": "22", "protocol": "tcp", "tcp_flags": "syn", "action": "accept"}]`,
+		},
+		{
+			name:      "no rules",
+			rules:     `[]`,
+			expectErr: "At least one rule block is required",
+		},
+		{
+			name:      "tcp flags with udp",
+			rules:     `[{"protocol": "udp", "tcp_flags": "syn", "action": "accept"}]`,
+			expectErr: "tcp_flags requires protocol tcp",
+		},
+		{
+			name:      "invalid port",
+			rules:     `[{"dst_port": "22-", "action": "accept"}]`,
+			expectErr: "Invalid port or port range",
+		},
+		{
+			name:      "invalid action",
+			rules:     `[{"action": "drop"}]`,
+			expectErr: "expected one of accept, discard",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := providerServer.ValidateResourceConfig(context.Background(), &tfprotov6.ValidateResourceConfigRequest{
+				TypeName: "hetznerrobot_firewall",
+				Config: testDynamicValue(t, firewallType, fmt.Sprintf(
+					`{"server_ip": "1.2.3.4", "active": true, "whitelist_hos": false, "rule": %s}`, tt.rules)),
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var messages []string
+			for _, diagnostic := range resp.Diagnostics {
+				messages = append(messages, diagnostic.Summary+": "+diagnostic.Detail)
+			}
+			if tt.expectErr == "" && len(messages) != 0 {
+				t.Fatalf("Unexpected diagnostics: %v", messages)
+			}
+			if tt.expectErr != "" && !strings.Contains(strings.Join(messages, "\n"), tt.expectErr) {
+				t.Fatalf("Expected a diagnostic containing '%s', got: %v", tt.expectErr, messages)
+			}
+		})
+	}
+}
+
+// testFirewallProviderServer returns the provider server, configured for the
+// Robot webservice at url.
+func testFirewallProviderServer(t *testing.T, url string) tfprotov6.ProviderServer {
+	t.Helper()
+
+	providerServer, err := ProviderServer(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server := providerServer()
+	schemas, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	resp, err := server.ConfigureProvider(context.Background(), &tfprotov6.ConfigureProviderRequest{
+		Config: testDynamicValue(t, schemas.Provider.ValueType(), fmt.Sprintf(
+			`{"url": %q, "username": "acc-user", "password": "acc-pass"}`, url)),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, diagnostic := range resp.Diagnostics {
+		t.Fatalf("Unexpected diagnostic: %s: %s", diagnostic.Summary, diagnostic.Detail)
+	}
+	return server
+}
+
+// testDynamicValue encodes a JSON object of type typ, attributes it leaves
+// out are null.
+func testDynamicValue(t *testing.T, typ tftypes.Type, data string) *tfprotov6.DynamicValue {
+	t.Helper()
+
+	value, err := (&tfprotov6.RawState{JSON: []byte(data)}).Unmarshal(typ)
+	if err != nil {
+		t.Fatalf("Unable to decode %s: %v", data, err)
+	}
+	dynamicValue, err := tfprotov6.NewDynamicValue(typ, value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return &dynamicValue
+}
+
+func testDynamicValueValue(t *testing.T, typ tftypes.Type, dynamicValue *tfprotov6.DynamicValue) tftypes.Value {
+	t.Helper()
+
+	value, err := dynamicValue.Unmarshal(typ)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return value
+}
@@ -3,14 +3,18 @@ package hetznerrobot
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceVSwitch() *schema.Resource {
-	return &schema.Resource{
+	return withStateUpgraderV0(&schema.Resource{
 		CreateContext: resourceVSwitchCreate,
 		ReadContext:   resourceVSwitchRead,
 		UpdateContext: resourceVSwitchUpdate,
@@ -21,6 +25,11 @@ func resourceVSwitch() *schema.Resource {
 			StateContext: resourceVSwitchImportState,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -32,6 +41,14 @@ func resourceVSwitch() *schema.Resource {
 				Optional:    true,
 				Description: "VLAN ID",
 			},
+			"cancellation_date": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  vSwitchCancellationNow,
+				Description: "Date (YYYY-MM-DD) the vSwitch is canceled on when the resource is destroyed, " +
+					"or \"now\" to cancel it immediately",
+				ValidateDiagFunc: validateVSwitchCancellationDate,
+			},
 			// computed / read-only fields
 			"is_canceled": {
 				Type:        schema.TypeBool,
@@ -39,9 +56,10 @@ func resourceVSwitch() *schema.Resource {
 				Description: "Cancellation status",
 			},
 			"servers": {
-				Type:        schema.TypeList,
-				Description: "Attached server list",
-				Optional:    true,
+				Type: schema.TypeList,
+				Description: "Attached server list. Servers attached otherwise, e.g. with " +
+					"hetznerrobot_vswitch_attachment, are left alone; a server must not be managed by both",
+				Optional: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"server_number": {
@@ -110,7 +128,7 @@ func resourceVSwitch() *schema.Resource {
 				},
 			},
 		},
-	}
+	}, resourceVSwitchV0(), resourceVSwitchStateUpgradeV0)
 }
 
 func resourceVSwitchImportState(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
@@ -128,9 +146,10 @@ func resourceVSwitchImportState(ctx context.Context, d *schema.ResourceData, met
 	_ = d.Set("name", vSwitch.Name)
 	_ = d.Set("vlan", vSwitch.Vlan)
 	_ = d.Set("is_canceled", vSwitch.Canceled)
-	_ = d.Set("servers", vSwitch.Server)
-	_ = d.Set("subnets", vSwitch.Subnet)
-	_ = d.Set("cloud_networks", vSwitch.CloudNetwork)
+	_ = d.Set("cancellation_date", vSwitchCancellationNow)
+	_ = d.Set("servers", flattenVSwitchServers(vSwitch.Server, nil))
+	_ = d.Set("subnets", flattenVSwitchSubnets(vSwitch.Subnet))
+	_ = d.Set("cloud_networks", flattenVSwitchCloudNetworks(vSwitch.CloudNetwork))
 
 	results := make([]*schema.ResourceData, 1)
 	results[0] = d
@@ -149,17 +168,15 @@ func resourceVSwitchCreate(ctx context.Context, d *schema.ResourceData, meta any
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("unable to create VSwitch: %w", err))
 	}
-
-	_ = d.Set("is_canceled", vSwitch.Canceled)
-	_ = d.Set("servers", vSwitch.Server)
-	_ = d.Set("subnets", vSwitch.Subnet)
-	_ = d.Set("cloud_networks", vSwitch.CloudNetwork)
 	d.SetId(strconv.Itoa(vSwitch.ID))
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	servers, _ := d.Get("servers").([]any)
+	serversToAdd, _ := diffVSwitchServers(nil, servers)
+	if diags := changeVSwitchServers(ctx, c, d.Id(), serversToAdd, nil, d.Timeout(schema.TimeoutCreate)); diags.HasError() {
+		return append(diags, resourceVSwitchRead(ctx, d, meta)...)
+	}
 
-	return diags
+	return resourceVSwitchRead(ctx, d, meta)
 }
 
 func resourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -176,14 +193,28 @@ func resourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta any)
 
 	_ = d.Set("name", vSwitch.Name)
 	_ = d.Set("vlan", vSwitch.Vlan)
-	_ = d.Set("canceled", vSwitch.Canceled)
-	_ = d.Set("servers", vSwitch.Server)
-	_ = d.Set("subnets", vSwitch.Subnet)
-	_ = d.Set("cloud_networks", vSwitch.CloudNetwork)
+	_ = d.Set("is_canceled", vSwitch.Canceled)
+	// only the servers of this resource are recorded, the previous ones too
+	// while updating so that servers which failed to detach stay in state
+	o, n := d.GetChange("servers")
+	oldServers, _ := o.([]any)
+	newServers, _ := n.([]any)
+	serverNumbers := vSwitchServerNumbers(append(slices.Clone(newServers), oldServers...))
+	_ = d.Set("servers", flattenVSwitchServers(filterVSwitchServers(vSwitch.Server, serverNumbers), serverNumbers))
+	_ = d.Set("subnets", flattenVSwitchSubnets(vSwitch.Subnet))
+	_ = d.Set("cloud_networks", flattenVSwitchCloudNetworks(vSwitch.CloudNetwork))
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
+	if vSwitch.Canceled {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("VSwitch %s is pending cancellation", vSwitchID),
+			Detail:   "Robot reports the vSwitch as canceled. It keeps working until the cancellation date and is removed afterwards.",
+		})
+	}
+
 	return diags
 }
 
@@ -194,11 +225,14 @@ func resourceVSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta any
 	}
 
 	vSwitchID := d.Id()
-	name, _ := d.Get("name").(string)
-	vlan, _ := d.Get("vlan").(int)
-	err := c.updateVSwitch(ctx, vSwitchID, name, vlan)
-	if err != nil {
-		return diag.Errorf("Unable to update VSwitch:\n\t %q", err)
+
+	if d.HasChanges("name", "vlan") {
+		name, _ := d.Get("name").(string)
+		vlan, _ := d.Get("vlan").(int)
+		err := c.updateVSwitch(ctx, vSwitchID, name, vlan)
+		if err != nil {
+			return diag.Errorf("Unable to update VSwitch:\n\t %q", err)
+		}
 	}
 
 	if d.HasChange("servers") {
@@ -207,62 +241,11 @@ func resourceVSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta any
 		oldServers, _ := o.([]any)
 		newServers, _ := n.([]any)
 
-		mb := make(map[int]struct{}, len(newServers))
-		for _, x := range newServers {
-			srv, ok := x.(map[string]any)
-			if !ok {
-				continue
-			}
-			if serverNum, ok := srv["server_number"].(int); ok {
-				mb[serverNum] = struct{}{}
-			}
-		}
-		var serversToRemove []HetznerRobotVSwitchServer
-		for _, x := range oldServers {
-			srv, ok := x.(map[string]any)
-			if !ok {
-				continue
-			}
-			srvNum, ok := srv["server_number"].(int)
-			if !ok {
-				continue
-			}
-			if _, found := mb[srvNum]; !found {
-				serversToRemove = append(serversToRemove, HetznerRobotVSwitchServer{ServerNumber: srvNum})
-			}
-		}
-
-		if err := c.removeVSwitchServers(ctx, vSwitchID, serversToRemove); err != nil {
-			diag.Errorf("Unable to remove servers from VSwitch:\n\t %q", err)
-		}
-
-		ma := make(map[int]struct{}, len(oldServers))
-		for _, x := range oldServers {
-			srv, ok := x.(map[string]any)
-			if !ok {
-				continue
-			}
-			if serverNum, ok := srv["server_number"].(int); ok {
-				ma[serverNum] = struct{}{}
-			}
-		}
-		var serversToAdd []HetznerRobotVSwitchServer
-		for _, x := range newServers {
-			srv, ok := x.(map[string]any)
-			if !ok {
-				continue
-			}
-			srvNum, ok := srv["server_number"].(int)
-			if !ok {
-				continue
-			}
-			if _, found := ma[srvNum]; !found {
-				serversToAdd = append(serversToAdd, HetznerRobotVSwitchServer{ServerNumber: srvNum})
-			}
-		}
-
-		if err := c.addVSwitchServers(ctx, vSwitchID, serversToAdd); err != nil {
-			diag.Errorf("Unable to add servers to VSwitch:\n\t %q", err)
+		serversToAdd, serversToRemove := diffVSwitchServers(oldServers, newServers)
+		if diags := changeVSwitchServers(ctx, c, vSwitchID, serversToAdd, serversToRemove, d.Timeout(schema.TimeoutUpdate)); diags.HasError() {
+			// Read records the servers Robot reports, so the next plan
+			// retries what did not happen
+			return append(diags, resourceVSwitchRead(ctx, d, meta)...)
 		}
 	}
 
@@ -276,7 +259,8 @@ func resourceVSwitchDelete(ctx context.Context, d *schema.ResourceData, meta any
 	}
 
 	vSwitchID := d.Id()
-	err := c.deleteVSwitch(ctx, vSwitchID)
+	cancellationDate, _ := d.Get("cancellation_date").(string)
+	err := c.deleteVSwitch(ctx, vSwitchID, cancellationDate)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("unable to find VSwitch with ID %s: %w", vSwitchID, err))
 	}
@@ -286,3 +270,240 @@ func resourceVSwitchDelete(ctx context.Context, d *schema.ResourceData, meta any
 
 	return diags
 }
+
+// flattenVSwitchServers lists servers in the order of serverNumbers first, so
+// that the servers argument does not show a diff when Robot returns attached
+// servers in a different order than they are configured in.
+func flattenVSwitchServers(servers []HetznerRobotVSwitchServer, serverNumbers []int) []map[string]any {
+	sorted := slices.Clone(servers)
+	slices.SortStableFunc(sorted, func(a, b HetznerRobotVSwitchServer) int {
+		ia, ib := slices.Index(serverNumbers, a.ServerNumber), slices.Index(serverNumbers, b.ServerNumber)
+		switch {
+		case ia == ib:
+			return 0
+		case ia < 0:
+			return 1
+		case ib < 0:
+			return -1
+		}
+		return ia - ib
+	})
+
+	result := make([]map[string]any, 0, len(sorted))
+	for _, server := range sorted {
+		result = append(result, map[string]any{
+			"server_number":   server.ServerNumber,
+			"server_ip":       server.ServerIP,
+			"server_ipv6_net": server.ServerIPv6Net,
+			"status":          server.Status,
+		})
+	}
+	return result
+}
+
+func flattenVSwitchSubnets(subnets []HetznerRobotVSwitchSubnet) []map[string]any {
+	result := make([]map[string]any, 0, len(subnets))
+	for _, subnet := range subnets {
+		result = append(result, map[string]any{
+			"ip":      subnet.IP,
+			"mask":    subnet.Mask,
+			"gateway": subnet.Gateway,
+		})
+	}
+	return result
+}
+
+func flattenVSwitchCloudNetworks(cloudNetworks []HetznerRobotVSwitchCloudNetwork) []map[string]any {
+	result := make([]map[string]any, 0, len(cloudNetworks))
+	for _, cloudNetwork := range cloudNetworks {
+		result = append(result, map[string]any{
+			"id":      cloudNetwork.ID,
+			"ip":      cloudNetwork.IP,
+			"mask":    cloudNetwork.Mask,
+			"gateway": cloudNetwork.Gateway,
+		})
+	}
+	return result
+}
+
+// diffVSwitchServers returns the servers only present in newServers and the
+// servers only present in oldServers.
+func diffVSwitchServers(oldServers []any, newServers []any) ([]HetznerRobotVSwitchServer, []HetznerRobotVSwitchServer) {
+	oldNumbers := vSwitchServerNumbers(oldServers)
+	newNumbers := vSwitchServerNumbers(newServers)
+
+	var serversToAdd []HetznerRobotVSwitchServer
+	for _, serverNumber := range newNumbers {
+		if !slices.Contains(oldNumbers, serverNumber) {
+			serversToAdd = append(serversToAdd, HetznerRobotVSwitchServer{ServerNumber: serverNumber})
+		}
+	}
+	var serversToRemove []HetznerRobotVSwitchServer
+	for _, serverNumber := range oldNumbers {
+		if !slices.Contains(newNumbers, serverNumber) {
+			serversToRemove = append(serversToRemove, HetznerRobotVSwitchServer{ServerNumber: serverNumber})
+		}
+	}
+	return serversToAdd, serversToRemove
+}
+
+func vSwitchServerNumbers(servers []any) []int {
+	serverNumbers := make([]int, 0, len(servers))
+	for _, x := range servers {
+		srv, ok := x.(map[string]any)
+		if !ok {
+			continue
+		}
+		serverNumber, ok := srv["server_number"].(int)
+		if !ok || slices.Contains(serverNumbers, serverNumber) {
+			continue
+		}
+		serverNumbers = append(serverNumbers, serverNumber)
+	}
+	return serverNumbers
+}
+
+// changeVSwitchServers detaches and attaches servers in batches of at most
+// vSwitchServerBatchSize and waits for Robot to process them. Every failed
+// batch is reported, the remaining batches are still attempted.
+func changeVSwitchServers(ctx context.Context, c HetznerRobotClient, id string, serversToAdd []HetznerRobotVSwitchServer, serversToRemove []HetznerRobotVSwitchServer, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var changed []int
+
+	for _, batch := range batchVSwitchServers(serversToRemove, vSwitchServerBatchSize) {
+		if err := c.removeVSwitchServers(ctx, id, batch); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to remove servers %v from VSwitch %s", vSwitchServerNumbersOf(batch), id),
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		changed = append(changed, vSwitchServerNumbersOf(batch)...)
+	}
+
+	for _, batch := range batchVSwitchServers(serversToAdd, vSwitchServerBatchSize) {
+		if err := c.addVSwitchServers(ctx, id, batch); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to add servers %v to VSwitch %s", vSwitchServerNumbersOf(batch), id),
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		changed = append(changed, vSwitchServerNumbersOf(batch)...)
+	}
+
+	if len(changed) > 0 {
+		diags = append(diags, waitForVSwitchServers(ctx, c, id, changed, timeout)...)
+	}
+	return diags
+}
+
+func batchVSwitchServers(servers []HetznerRobotVSwitchServer, size int) [][]HetznerRobotVSwitchServer {
+	var batches [][]HetznerRobotVSwitchServer
+	for len(servers) > 0 {
+		n := min(size, len(servers))
+		batches = append(batches, servers[:n])
+		servers = servers[n:]
+	}
+	return batches
+}
+
+func vSwitchServerNumbersOf(servers []HetznerRobotVSwitchServer) []int {
+	serverNumbers := make([]int, 0, len(servers))
+	for _, server := range servers {
+		serverNumbers = append(serverNumbers, server.ServerNumber)
+	}
+	return serverNumbers
+}
+
+// waitForVSwitchServers polls the vSwitch until none of the given servers, or
+// any attached server if serverNumbers is nil, is still being processed by
+// Robot. Servers which ended up failed are reported as one error each.
+func waitForVSwitchServers(ctx context.Context, c HetznerRobotClient, id string, serverNumbers []int, timeout time.Duration) diag.Diagnostics {
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{vSwitchServerStatusInProcess},
+		Target:  []string{vSwitchServerStatusReady, vSwitchServerStatusFailed},
+		Refresh: func() (any, string, error) {
+			vSwitch, err := c.getVSwitch(withoutResponseCache(ctx), id)
+			if err != nil {
+				return nil, "", err
+			}
+			return vSwitch, vSwitchServersStatus(filterVSwitchServers(vSwitch.Server, serverNumbers)), nil
+		},
+		Timeout:      timeout,
+		PollInterval: vSwitchPollInterval,
+	}
+
+	result, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return diag.Errorf("Unable to wait for VSwitch %s servers to be ready:\n\t %q", id, err)
+	}
+
+	vSwitch, ok := result.(*HetznerRobotVSwitch)
+	if !ok {
+		return diag.Errorf("Unexpected VSwitch result type %T", result)
+	}
+
+	var diags diag.Diagnostics
+	for _, server := range filterVSwitchServers(vSwitch.Server, serverNumbers) {
+		if server.Status != vSwitchServerStatusFailed {
+			continue
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Server %d failed to attach to VSwitch %s", server.ServerNumber, id),
+			Detail:   fmt.Sprintf("Robot reports status %q for server %d (%s) on VSwitch %s.", server.Status, server.ServerNumber, server.ServerIP, id),
+		})
+	}
+	return diags
+}
+
+// vSwitchServersStatus folds the status of all attached servers into one:
+// in process while any server is, failed if any server failed, else ready.
+func vSwitchServersStatus(servers []HetznerRobotVSwitchServer) string {
+	status := vSwitchServerStatusReady
+	for _, server := range servers {
+		switch server.Status {
+		case vSwitchServerStatusReady:
+		case vSwitchServerStatusFailed:
+			status = vSwitchServerStatusFailed
+		default:
+			return vSwitchServerStatusInProcess
+		}
+	}
+	return status
+}
+
+func filterVSwitchServers(servers []HetznerRobotVSwitchServer, serverNumbers []int) []HetznerRobotVSwitchServer {
+	if serverNumbers == nil {
+		return servers
+	}
+	filtered := make([]HetznerRobotVSwitchServer, 0, len(serverNumbers))
+	for _, server := range servers {
+		if slices.Contains(serverNumbers, server.ServerNumber) {
+			filtered = append(filtered, server)
+		}
+	}
+	return filtered
+}
+
+func validateVSwitchCancellationDate(v any, path cty.Path) diag.Diagnostics {
+	value, ok := v.(string)
+	if !ok {
+		return diag.Errorf("expected type of %v to be string", v)
+	}
+	if value == vSwitchCancellationNow {
+		return nil
+	}
+	if _, err := time.Parse(vSwitchCancellationDateFormat, value); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("Invalid cancellation_date %q", value),
+			Detail:        fmt.Sprintf("Expected a date in the format YYYY-MM-DD or %q.", vSwitchCancellationNow),
+			AttributePath: path,
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceVSwitchAttachment() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVSwitchAttachmentCreate,
+		ReadContext:   resourceVSwitchAttachmentRead,
+		DeleteContext: resourceVSwitchAttachmentDelete,
+		Description: "Attaches a single Hetzner Robot server to a vSwitch. " +
+			"Do not also list the server in the servers argument of hetznerrobot_vswitch, " +
+			"as both would try to own its attachment.",
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVSwitchAttachmentImportState,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vswitch_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "vSwitch ID",
+			},
+			"server_number": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Server number",
+			},
+			// computed / read-only fields
+			"server_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server main IPv4 address",
+			},
+			"server_ipv6_net": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server main IPv6 net address",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Attachment status (\"ready\", \"in process\" or \"failed\")",
+			},
+		},
+	}
+}
+
+func resourceVSwitchAttachmentImportState(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	vSwitchID, serverNumber, err := parseVSwitchAttachmentID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	_ = d.Set("vswitch_id", vSwitchID)
+	_ = d.Set("server_number", serverNumber)
+
+	results := make([]*schema.ResourceData, 1)
+	results[0] = d
+	return results, nil
+}
+
+func resourceVSwitchAttachmentCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	c, ok := meta.(HetznerRobotClient)
+	if !ok {
+		return diag.Errorf("Unable to cast meta to HetznerRobotClient")
+	}
+
+	vSwitchNumber, _ := d.Get("vswitch_id").(int)
+	vSwitchID := strconv.Itoa(vSwitchNumber)
+	serverNumber, _ := d.Get("server_number").(int)
+
+	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
+	if err != nil {
+		return diag.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err)
+	}
+	for _, server := range vSwitch.Server {
+		if server.ServerNumber == serverNumber {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Server %d is already attached to VSwitch %s", serverNumber, vSwitchID),
+				Detail: "The attachment is managed elsewhere, e.g. by the servers argument of hetznerrobot_vswitch or another state. " +
+					fmt.Sprintf("Import it with the ID %q to manage it here.", vSwitchAttachmentID(vSwitchID, serverNumber)),
+			}}
+		}
+	}
+
+	servers := []HetznerRobotVSwitchServer{{ServerNumber: serverNumber}}
+	if err := c.addVSwitchServers(ctx, vSwitchID, servers); err != nil {
+		return diag.Errorf("Unable to attach server %d to VSwitch %s:\n\t %q", serverNumber, vSwitchID, err)
+	}
+	d.SetId(vSwitchAttachmentID(vSwitchID, serverNumber))
+
+	if diags := waitForVSwitchServers(ctx, c, vSwitchID, []int{serverNumber}, d.Timeout(schema.TimeoutCreate)); diags.HasError() {
+		return append(diags, resourceVSwitchAttachmentRead(ctx, d, meta)...)
+	}
+
+	return resourceVSwitchAttachmentRead(ctx, d, meta)
+}
+
+func resourceVSwitchAttachmentRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	c, ok := meta.(HetznerRobotClient)
+	if !ok {
+		return diag.Errorf("Unable to cast meta to HetznerRobotClient")
+	}
+
+	vSwitchNumber, _ := d.Get("vswitch_id").(int)
+	vSwitchID := strconv.Itoa(vSwitchNumber)
+	serverNumber, _ := d.Get("server_number").(int)
+
+	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
+	var apiErr *HetznerRobotAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		// the vSwitch was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("unable to find VSwitch with ID %s: %w", vSwitchID, err))
+	}
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	for _, server := range vSwitch.Server {
+		if server.ServerNumber != serverNumber {
+			continue
+		}
+		_ = d.Set("server_ip", server.ServerIP)
+		_ = d.Set("server_ipv6_net", server.ServerIPv6Net)
+		_ = d.Set("status", server.Status)
+		return diags
+	}
+
+	// the server was detached outside of Terraform
+	d.SetId("")
+
+	return diags
+}
+
+func resourceVSwitchAttachmentDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	c, ok := meta.(HetznerRobotClient)
+	if !ok {
+		return diag.Errorf("Unable to cast meta to HetznerRobotClient")
+	}
+
+	vSwitchNumber, _ := d.Get("vswitch_id").(int)
+	vSwitchID := strconv.Itoa(vSwitchNumber)
+	serverNumber, _ := d.Get("server_number").(int)
+
+	servers := []HetznerRobotVSwitchServer{{ServerNumber: serverNumber}}
+	err := c.removeVSwitchServers(ctx, vSwitchID, servers)
+	var apiErr *HetznerRobotAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		// the vSwitch was deleted outside of Terraform
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("Unable to detach server %d from VSwitch %s:\n\t %q", serverNumber, vSwitchID, err)
+	}
+
+	return waitForVSwitchServers(ctx, c, vSwitchID, []int{serverNumber}, d.Timeout(schema.TimeoutDelete))
+}
+
+func vSwitchAttachmentID(vSwitchID string, serverNumber int) string {
+	return fmt.Sprintf("%s/%d", vSwitchID, serverNumber)
+}
+
+func parseVSwitchAttachmentID(id string) (int, int, error) {
+	vSwitchPart, serverPart, found := strings.Cut(id, "/")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid VSwitch attachment ID %q, expected <vswitch_id>/<server_number>", id)
+	}
+	vSwitchID, err := strconv.Atoi(vSwitchPart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid VSwitch ID in %q: %w", id, err)
+	}
+	serverNumber, err := strconv.Atoi(serverPart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid server number in %q: %w", id, err)
+	}
+	return vSwitchID, serverNumber, nil
+}
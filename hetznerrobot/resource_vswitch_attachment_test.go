@@ -0,0 +1,257 @@
+package hetznerrobot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestParseVSwitchAttachmentID(t *testing.T) {
+	tests := []struct {
+		id           string
+		vSwitchID    int
+		serverNumber int
+		expectErr    bool
+	}{
+		{id: "4321/123", vSwitchID: 4321, serverNumber: 123},
+		{id: "4321", expectErr: true},
+		{id: "abc/123", expectErr: true},
+		{id: "4321/abc", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			vSwitchID, serverNumber, err := parseVSwitchAttachmentID(tt.id)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if vSwitchID != tt.vSwitchID || serverNumber != tt.serverNumber {
+				t.Fatalf("Expected %d/%d, got %d/%d", tt.vSwitchID, tt.serverNumber, vSwitchID, serverNumber)
+			}
+		})
+	}
+}
+
+// newVSwitchAttachmentTestServer fakes a vSwitch whose servers become ready
+// on the second GET after being attached.
+func newVSwitchAttachmentTestServer(t *testing.T, attached ...int) *httptest.Server {
+	t.Helper()
+
+	var mu sync.Mutex
+	servers := map[int]int{}
+	for _, serverNumber := range attached {
+		servers[serverNumber] = 2
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path != "/vswitch/4321" && r.URL.Path != "/vswitch/4321/server" {
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+		// ParseForm ignores DELETE bodies, which Robot reads like POST bodies
+		body, _ := io.ReadAll(r.Body)
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		switch r.Method {
+		case "POST":
+			for _, s := range form["server"] {
+				serverNumber, _ := strconv.Atoi(s)
+				servers[serverNumber] = 0
+			}
+			w.WriteHeader(http.StatusAccepted)
+			return
+		case "DELETE":
+			for _, s := range form["server"] {
+				serverNumber, _ := strconv.Atoi(s)
+				delete(servers, serverNumber)
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		vSwitch := HetznerRobotVSwitch{ID: 4321, Name: "test", Vlan: 4000}
+		for serverNumber, polls := range servers {
+			status := "in process"
+			if polls >= 1 {
+				status = "ready"
+			}
+			servers[serverNumber] = polls + 1
+			vSwitch.Server = append(vSwitch.Server, HetznerRobotVSwitchServer{
+				ServerNumber: serverNumber,
+				ServerIP:     "1.2.3." + strconv.Itoa(serverNumber),
+				Status:       status,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(vSwitch)
+	}))
+}
+
+func TestResourceVSwitchAttachmentCreate(t *testing.T) {
+	setVSwitchPollInterval(t, 10*time.Millisecond)
+
+	server := newVSwitchAttachmentTestServer(t)
+	defer server.Close()
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	d := schema.TestResourceDataRaw(t, resourceVSwitchAttachment().Schema, map[string]any{
+		"vswitch_id":    4321,
+		"server_number": 7,
+	})
+	if diags := resourceVSwitchAttachmentCreate(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	if d.Id() != "4321/7" {
+		t.Fatalf("Expected ID 4321/7, got %s", d.Id())
+	}
+	if d.Get("status").(string) != "ready" {
+		t.Fatalf("Expected status ready, got %v", d.Get("status"))
+	}
+	if d.Get("server_ip").(string) != "1.2.3.7" {
+		t.Fatalf("Expected server IP 1.2.3.7, got %v", d.Get("server_ip"))
+	}
+
+	if diags := resourceVSwitchAttachmentDelete(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if diags := resourceVSwitchAttachmentRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Fatalf("Expected detached attachment to be removed from state, got ID %s", d.Id())
+	}
+}
+
+func TestResourceVSwitchAttachmentCreateConflict(t *testing.T) {
+	server := newVSwitchAttachmentTestServer(t, 7)
+	defer server.Close()
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	d := schema.TestResourceDataRaw(t, resourceVSwitchAttachment().Schema, map[string]any{
+		"vswitch_id":    4321,
+		"server_number": 7,
+	})
+	diags := resourceVSwitchAttachmentCreate(context.Background(), d, client)
+	if !diags.HasError() {
+		t.Fatal("Expected error for already attached server")
+	}
+	if diags[0].Summary != "Server 7 is already attached to VSwitch 4321" {
+		t.Fatalf("Unexpected error: %v", diags[0].Summary)
+	}
+}
+
+func TestResourceVSwitchAttachmentReadDeletedVSwitch(t *testing.T) {
+	server := newVSwitchAttachmentTestServer(t)
+	defer server.Close()
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	d := schema.TestResourceDataRaw(t, resourceVSwitchAttachment().Schema, map[string]any{
+		"vswitch_id":    1234,
+		"server_number": 7,
+	})
+	d.SetId("1234/7")
+	if diags := resourceVSwitchAttachmentRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Fatalf("Expected attachment of a deleted vSwitch to be removed from state, got ID %s", d.Id())
+	}
+}
+
+func TestResourceVSwitchAttachmentDeleteDeletedVSwitch(t *testing.T) {
+	server := newVSwitchAttachmentTestServer(t)
+	defer server.Close()
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	d := schema.TestResourceDataRaw(t, resourceVSwitchAttachment().Schema, map[string]any{
+		"vswitch_id":    1234,
+		"server_number": 7,
+	})
+	d.SetId("1234/7")
+	if diags := resourceVSwitchAttachmentDelete(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+}
+
+func TestAccResourceVSwitchAttachment(t *testing.T) {
+	mock, providerConfig := testAccRobotMock(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckVSwitchDestroy(mock),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "hetznerrobot_vswitch" "test" {
+  name = "acc-test"
+  vlan = 4000
+}
+
+resource "hetznerrobot_vswitch_attachment" "test" {
+  vswitch_id    = hetznerrobot_vswitch.test.id
+  server_number = %d
+}
+`, testAccServerNumber),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch_attachment.test", "server_ip", testAccServerIP),
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch_attachment.test", "status", vSwitchServerStatusReady),
+					func(state *terraform.State) error {
+						id, _ := strconv.Atoi(state.RootModule().Resources["hetznerrobot_vswitch.test"].Primary.ID)
+						vSwitch, _ := mock.VSwitch(id)
+						if vSwitch.Servers[testAccServerNumber] != vSwitchServerStatusReady {
+							return fmt.Errorf("expected server %d to be attached, got %v", testAccServerNumber, vSwitch.Servers)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "hetznerrobot_vswitch_attachment.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// detaching leaves the vSwitch in place
+				Config: providerConfig + `
+resource "hetznerrobot_vswitch" "test" {
+  name = "acc-test"
+  vlan = 4000
+}
+`,
+				Check: func(state *terraform.State) error {
+					id, _ := strconv.Atoi(state.RootModule().Resources["hetznerrobot_vswitch.test"].Primary.ID)
+					if vSwitch, _ := mock.VSwitch(id); len(vSwitch.Servers) != 0 {
+						return fmt.Errorf("expected no attached servers, got %v", vSwitch.Servers)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,660 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/robotmock"
+)
+
+func TestVSwitchServersStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		expected string
+	}{
+		{"no servers", nil, "ready"},
+		{"all ready", []string{"ready", "ready"}, "ready"},
+		{"one in process", []string{"ready", "in process"}, "in process"},
+		{"in process before failed", []string{"failed", "in process"}, "in process"},
+		{"one failed", []string{"ready", "failed"}, "failed"},
+		{"unknown status", []string{"ready", "processing"}, "in process"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servers := make([]HetznerRobotVSwitchServer, 0, len(tt.statuses))
+			for i, status := range tt.statuses {
+				servers = append(servers, HetznerRobotVSwitchServer{ServerNumber: i + 1, Status: status})
+			}
+			if result := vSwitchServersStatus(servers); result != tt.expected {
+				t.Fatalf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// setVSwitchPollInterval shortens vSwitchPollInterval for the duration of
+// the test.
+func setVSwitchPollInterval(t *testing.T, interval time.Duration) {
+	t.Helper()
+	previous := vSwitchPollInterval
+	vSwitchPollInterval = interval
+	t.Cleanup(func() { vSwitchPollInterval = previous })
+}
+
+func TestWaitForVSwitchServers(t *testing.T) {
+	setVSwitchPollInterval(t, 10*time.Millisecond)
+
+	tests := []struct {
+		name        string
+		responses   [][]string
+		expectDiags []string
+	}{
+		{
+			name:      "becomes ready",
+			responses: [][]string{{"in process", "ready"}, {"in process", "in process"}, {"ready", "ready"}},
+		},
+		{
+			name:        "one server fails",
+			responses:   [][]string{{"in process", "in process"}, {"ready", "failed"}},
+			expectDiags: []string{"Server 2 failed to attach to VSwitch 4321"},
+		},
+		{
+			name:        "all servers fail",
+			responses:   [][]string{{"failed", "failed"}},
+			expectDiags: []string{"Server 1 failed to attach to VSwitch 4321", "Server 2 failed to attach to VSwitch 4321"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				idx := int(calls.Add(1)) - 1
+				if idx >= len(tt.responses) {
+					idx = len(tt.responses) - 1
+				}
+				servers := make([]string, 0)
+				for i, status := range tt.responses[idx] {
+					servers = append(servers, fmt.Sprintf(`{"server_number": %d, "server_ip": "1.2.3.%d", "status": %q}`, i+1, i+1, status))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"id": 4321, "name": "test", "vlan": 4000, "server": [%s]}`, strings.Join(servers, ","))
+			}))
+			defer server.Close()
+
+			client := NewHetznerRobotClient("user", "pass", server.URL)
+			diags := waitForVSwitchServers(context.Background(), client, "4321", nil, time.Second)
+
+			if len(diags) != len(tt.expectDiags) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(tt.expectDiags), len(diags), diags)
+			}
+			for i, summary := range tt.expectDiags {
+				if diags[i].Summary != summary {
+					t.Errorf("Expected diagnostic '%s', got '%s'", summary, diags[i].Summary)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForVSwitchServersTimeout(t *testing.T) {
+	setVSwitchPollInterval(t, 10*time.Millisecond)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 4321, "server": [{"server_number": 1, "status": "in process"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	diags := waitForVSwitchServers(context.Background(), client, "4321", nil, 100*time.Millisecond)
+	if !diags.HasError() {
+		t.Fatal("Expected timeout error but got none")
+	}
+	if !strings.Contains(diags[0].Summary, "timeout while waiting for state") {
+		t.Fatalf("Expected timeout error, got: %v", diags[0].Summary)
+	}
+}
+
+func TestValidateVSwitchCancellationDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		isValid bool
+	}{
+		{"now", true},
+		{"2026-12-31", true},
+		{"2026-02-30", false},
+		{"31.12.2026", false},
+		{"2026-12-31T00:00:00Z", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			diags := validateVSwitchCancellationDate(tt.value, cty.Path{})
+			if tt.isValid && diags.HasError() {
+				t.Fatalf("Expected %q to be valid, got: %v", tt.value, diags)
+			}
+			if !tt.isValid && !diags.HasError() {
+				t.Fatalf("Expected %q to be invalid", tt.value)
+			}
+		})
+	}
+}
+
+func TestResourceVSwitchReadCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 4321, "name": "test", "vlan": 4000, "canceled": true, "server": []}`))
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+	d := schema.TestResourceDataRaw(t, resourceVSwitch().Schema, map[string]any{
+		"name": "test",
+		"vlan": 4000,
+	})
+	d.SetId("4321")
+
+	diags := resourceVSwitchRead(context.Background(), d, client)
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if !d.Get("is_canceled").(bool) {
+		t.Fatal("Expected is_canceled to be true")
+	}
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || diags[0].Summary != "VSwitch 4321 is pending cancellation" {
+		t.Fatalf("Expected pending cancellation warning, got: %v", diags)
+	}
+}
+
+func TestDeleteVSwitchCancellationDate(t *testing.T) {
+	tests := []struct {
+		name             string
+		cancellationDate string
+		expected         string
+	}{
+		{"now", "now", "now"},
+		{"date", "2026-12-31", "2026-12-31"},
+		{"default", "", "now"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var form url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "DELETE" || r.URL.Path != "/vswitch/4321" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				body, _ := io.ReadAll(r.Body)
+				form, _ = url.ParseQuery(string(body))
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := NewHetznerRobotClient("user", "pass", server.URL)
+			if err := client.deleteVSwitch(context.Background(), "4321", tt.cancellationDate); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if form.Get("cancellation_date") != tt.expected {
+				t.Fatalf("Expected cancellation_date=%s, got %q", tt.expected, form.Get("cancellation_date"))
+			}
+		})
+	}
+}
+
+func TestDiffVSwitchServers(t *testing.T) {
+	servers := func(serverNumbers ...int) []any {
+		result := make([]any, 0, len(serverNumbers))
+		for _, serverNumber := range serverNumbers {
+			result = append(result, map[string]any{"server_number": serverNumber})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name           string
+		oldServers     []any
+		newServers     []any
+		expectedAdd    []int
+		expectedRemove []int
+	}{
+		{"unchanged", servers(1, 2), servers(2, 1), []int{}, []int{}},
+		{"added", servers(1), servers(1, 2, 3), []int{2, 3}, []int{}},
+		{"removed", servers(1, 2, 3), servers(2), []int{}, []int{1, 3}},
+		{"replaced", servers(1, 2), servers(2, 3), []int{3}, []int{1}},
+		{"duplicates", servers(), servers(1, 1), []int{1}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serversToAdd, serversToRemove := diffVSwitchServers(tt.oldServers, tt.newServers)
+			if !slices.Equal(vSwitchServerNumbersOf(serversToAdd), tt.expectedAdd) {
+				t.Errorf("Expected to add %v, got %v", tt.expectedAdd, vSwitchServerNumbersOf(serversToAdd))
+			}
+			if !slices.Equal(vSwitchServerNumbersOf(serversToRemove), tt.expectedRemove) {
+				t.Errorf("Expected to remove %v, got %v", tt.expectedRemove, vSwitchServerNumbersOf(serversToRemove))
+			}
+		})
+	}
+}
+
+func TestBatchVSwitchServers(t *testing.T) {
+	servers := make([]HetznerRobotVSwitchServer, 0)
+	for i := range 25 {
+		servers = append(servers, HetznerRobotVSwitchServer{ServerNumber: i})
+	}
+
+	batches := batchVSwitchServers(servers, 10)
+	if len(batches) != 3 || len(batches[0]) != 10 || len(batches[1]) != 10 || len(batches[2]) != 5 {
+		t.Fatalf("Expected batches of 10, 10 and 5, got %v", batches)
+	}
+	if len(batchVSwitchServers(nil, 10)) != 0 {
+		t.Fatal("Expected no batches for no servers")
+	}
+}
+
+func TestChangeVSwitchServers(t *testing.T) {
+	setVSwitchPollInterval(t, 10*time.Millisecond)
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": 4321, "server": [{"server_number": 1, "status": "ready"}]}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		requests = append(requests, r.Method+" "+strings.Join(form["server"], ","))
+		if slices.Contains(form["server"], "13") {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error": {"status": 409, "code": "VSWITCH_SERVER_LIMIT_REACHED", "message": "server limit reached"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	t.Run("no changes", func(t *testing.T) {
+		requests = nil
+		if diags := changeVSwitchServers(context.Background(), client, "4321", nil, nil, time.Second); diags.HasError() {
+			t.Fatalf("Unexpected error: %v", diags)
+		}
+		if len(requests) != 0 {
+			t.Fatalf("Expected no requests, got %v", requests)
+		}
+	})
+
+	t.Run("failed batch", func(t *testing.T) {
+		requests = nil
+		serversToAdd := make([]HetznerRobotVSwitchServer, 0)
+		for i := 1; i <= 15; i++ {
+			serversToAdd = append(serversToAdd, HetznerRobotVSwitchServer{ServerNumber: i})
+		}
+		serversToRemove := []HetznerRobotVSwitchServer{{ServerNumber: 20}}
+
+		diags := changeVSwitchServers(context.Background(), client, "4321", serversToAdd, serversToRemove, time.Second)
+
+		expectedRequests := []string{
+			"DELETE 20",
+			"POST 1,2,3,4,5,6,7,8,9,10",
+			"POST 11,12,13,14,15",
+		}
+		if !slices.Equal(requests, expectedRequests) {
+			t.Fatalf("Expected requests %v, got %v", expectedRequests, requests)
+		}
+		if len(diags) != 1 || diags[0].Summary != "Unable to add servers [11 12 13 14 15] to VSwitch 4321" {
+			t.Fatalf("Expected one failed batch, got: %v", diags)
+		}
+		if !strings.Contains(diags[0].Detail, "VSWITCH_SERVER_LIMIT_REACHED") {
+			t.Fatalf("Expected Robot error in detail, got: %s", diags[0].Detail)
+		}
+	})
+}
+
+func TestResourceVSwitchUpdateFailedBatch(t *testing.T) {
+	setVSwitchPollInterval(t, 10*time.Millisecond)
+
+	attached := []int{20}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			servers := make([]string, 0, len(attached))
+			for _, serverNumber := range attached {
+				servers = append(servers, fmt.Sprintf(`{"server_number": %d, "status": "ready"}`, serverNumber))
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"id": 4321, "name": "test", "vlan": 4000, "server": [%s]}`, strings.Join(servers, ","))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		// the second batch hits the server limit
+		if slices.Contains(form["server"], "11") {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error": {"status": 409, "code": "VSWITCH_SERVER_LIMIT_REACHED", "message": "server limit reached"}}`))
+			return
+		}
+		for _, s := range form["server"] {
+			serverNumber, _ := strconv.Atoi(s)
+			if r.Method == "DELETE" {
+				attached = slices.DeleteFunc(attached, func(n int) bool { return n == serverNumber })
+			} else {
+				attached = append(attached, serverNumber)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	r := resourceVSwitch()
+	state := &terraform.InstanceState{
+		ID: "4321",
+		Attributes: map[string]string{
+			"id":                      "4321",
+			"name":                    "test",
+			"vlan":                    "4000",
+			"cancellation_date":       vSwitchCancellationNow,
+			"servers.#":               "1",
+			"servers.0.server_number": "20",
+			"servers.0.status":        "ready",
+		},
+	}
+	servers := make([]any, 0, 15)
+	for i := 1; i <= 15; i++ {
+		servers = append(servers, map[string]any{"server_number": i})
+	}
+	config := terraform.NewResourceConfigRaw(map[string]any{
+		"name":    "test",
+		"vlan":    4000,
+		"servers": servers,
+	})
+	diff, err := r.Diff(context.Background(), state, config, client)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	applied, diags := r.Apply(context.Background(), state, diff, client)
+	if !diags.HasError() {
+		t.Fatal("Expected error for the failed batch")
+	}
+
+	// state holds what Robot reports, so the next plan retries servers 11-15
+	if applied.Attributes["servers.#"] != "10" {
+		t.Fatalf("Expected 10 servers in state, got %v", applied.Attributes)
+	}
+	for i := 0; i < 10; i++ {
+		if got := applied.Attributes[fmt.Sprintf("servers.%d.server_number", i)]; got != strconv.Itoa(i+1) {
+			t.Fatalf("Expected servers.%d.server_number %d, got %s", i, i+1, got)
+		}
+	}
+}
+
+func TestResourceVSwitchCreateWithServers(t *testing.T) {
+	setVSwitchPollInterval(t, 10*time.Millisecond)
+
+	var requests []string
+	var attached []int
+	var polls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/vswitch":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"id": 4321, "name": %q, "vlan": %s, "server": []}`, form.Get("name"), form.Get("vlan"))
+		case r.Method == "POST" && r.URL.Path == "/vswitch/4321/server":
+			for _, s := range form["server"] {
+				var serverNumber int
+				fmt.Sscan(s, &serverNumber)
+				attached = append(attached, serverNumber)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == "GET" && r.URL.Path == "/vswitch/4321":
+			// servers become ready on the second poll
+			polls++
+			status := "in process"
+			if polls > 1 {
+				status = "ready"
+			}
+			servers := make([]string, 0, len(attached))
+			for i := len(attached) - 1; i >= 0; i-- {
+				serverNumber := attached[i]
+				servers = append(servers, fmt.Sprintf(`{"server_number": %d, "server_ip": "1.2.3.%d", "server_ipv6_net": "2a01:4f8:%d::", "status": %q}`, serverNumber, serverNumber, serverNumber, status))
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"id": 4321, "name": "test", "vlan": 4000, "server": [%s]}`, strings.Join(servers, ","))
+		default:
+			http.Error(w, "Not Found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	d := schema.TestResourceDataRaw(t, resourceVSwitch().Schema, map[string]any{
+		"name": "test",
+		"vlan": 4000,
+		"servers": []any{
+			map[string]any{"server_number": 7},
+			map[string]any{"server_number": 8},
+		},
+	})
+	if diags := resourceVSwitchCreate(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	expectedRequests := []string{
+		"POST /vswitch",
+		"POST /vswitch/4321/server",
+		"GET /vswitch/4321",
+		"GET /vswitch/4321",
+		"GET /vswitch/4321",
+	}
+	if !slices.Equal(requests, expectedRequests) {
+		t.Fatalf("Expected requests %v, got %v", expectedRequests, requests)
+	}
+	if !slices.Equal(attached, []int{7, 8}) {
+		t.Fatalf("Expected servers 7 and 8 to be attached, got %v", attached)
+	}
+
+	if d.Id() != "4321" {
+		t.Fatalf("Expected ID 4321, got %s", d.Id())
+	}
+	// Robot lists the servers in reverse order, state keeps the configured order
+	for i, serverNumber := range []int{7, 8} {
+		prefix := fmt.Sprintf("servers.%d.", i)
+		if d.Get(prefix+"server_number").(int) != serverNumber {
+			t.Fatalf("Expected %sserver_number %d, got %v", prefix, serverNumber, d.Get(prefix+"server_number"))
+		}
+		if d.Get(prefix+"status").(string) != "ready" {
+			t.Fatalf("Expected %sstatus ready, got %v", prefix, d.Get(prefix+"status"))
+		}
+		if expected := fmt.Sprintf("1.2.3.%d", serverNumber); d.Get(prefix+"server_ip").(string) != expected {
+			t.Fatalf("Expected %sserver_ip %s, got %v", prefix, expected, d.Get(prefix+"server_ip"))
+		}
+		if expected := fmt.Sprintf("2a01:4f8:%d::", serverNumber); d.Get(prefix+"server_ipv6_net").(string) != expected {
+			t.Fatalf("Expected %sserver_ipv6_net %s, got %v", prefix, expected, d.Get(prefix+"server_ipv6_net"))
+		}
+	}
+}
+
+func TestResourceVSwitchServersOwnership(t *testing.T) {
+	setVSwitchPollInterval(t, 10*time.Millisecond)
+
+	// server 8 is attached with hetznerrobot_vswitch_attachment
+	server := newVSwitchAttachmentTestServer(t, 7, 8)
+	defer server.Close()
+	client := NewHetznerRobotClient("user", "pass", server.URL)
+
+	r := resourceVSwitch()
+	state := &terraform.InstanceState{
+		ID: "4321",
+		Attributes: map[string]string{
+			"id":                        "4321",
+			"name":                      "test",
+			"vlan":                      "4000",
+			"cancellation_date":         vSwitchCancellationNow,
+			"servers.#":                 "1",
+			"servers.0.server_number":   "7",
+			"servers.0.server_ip":       "1.2.3.7",
+			"servers.0.server_ipv6_net": "",
+			"servers.0.status":          "ready",
+		},
+	}
+
+	refreshed, diags := r.RefreshWithoutUpgrade(context.Background(), state, client)
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if refreshed.Attributes["servers.#"] != "1" || refreshed.Attributes["servers.0.server_number"] != "7" {
+		t.Fatalf("Expected only server 7 in state, got %v", refreshed.Attributes)
+	}
+
+	// removing every servers block detaches the servers of this resource only
+	config := terraform.NewResourceConfigRaw(map[string]any{
+		"name": "test",
+		"vlan": 4000,
+	})
+	diff, err := r.Diff(context.Background(), refreshed, config, client)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	applied, diags := r.Apply(context.Background(), refreshed, diff, client)
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if applied.Attributes["servers.#"] != "0" {
+		t.Fatalf("Expected no servers in state, got %v", applied.Attributes)
+	}
+
+	vSwitch, err := client.getVSwitch(context.Background(), "4321")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := vSwitchServerNumbersOf(vSwitch.Server); !slices.Equal(got, []int{8}) {
+		t.Fatalf("Expected only server 8 to stay attached, got %v", got)
+	}
+}
+
+func TestAccResourceVSwitch(t *testing.T) {
+	mock, providerConfig := testAccRobotMock(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckVSwitchDestroy(mock),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "hetznerrobot_vswitch" "test" {
+  name = "acc-test"
+  vlan = 4000
+
+  servers {
+    server_number = %d
+  }
+}
+`, testAccServerNumber),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch.test", "name", "acc-test"),
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch.test", "is_canceled", "false"),
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch.test", "servers.#", "1"),
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch.test", "servers.0.server_ip", testAccServerIP),
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch.test", "servers.0.status", vSwitchServerStatusReady),
+				),
+			},
+			{
+				ResourceName:      "hetznerrobot_vswitch.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "hetznerrobot_vswitch" "test" {
+  name = "acc-test-renamed"
+  vlan = 4001
+
+  servers {
+    server_number = %d
+  }
+}
+`, testAccServerNumber2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch.test", "name", "acc-test-renamed"),
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch.test", "vlan", "4001"),
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch.test", "servers.#", "1"),
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch.test", "servers.0.server_number", strconv.Itoa(testAccServerNumber2)),
+					resource.TestCheckResourceAttr("hetznerrobot_vswitch.test", "servers.0.status", vSwitchServerStatusReady),
+				),
+			},
+			{
+				ResourceName:      "hetznerrobot_vswitch.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceVSwitchCancellationDate(t *testing.T) {
+	mock, providerConfig := testAccRobotMock(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckVSwitchDestroy(mock),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "hetznerrobot_vswitch" "test" {
+  name              = "acc-test"
+  vlan              = 4000
+  cancellation_date = "2099-12-31"
+}
+`,
+				Check: resource.TestCheckResourceAttr("hetznerrobot_vswitch.test", "cancellation_date", "2099-12-31"),
+			},
+		},
+	})
+}
+
+// testAccCheckVSwitchDestroy checks that every vSwitch in the state was
+// removed or, for a cancellation date in the future, marked as canceled.
+func testAccCheckVSwitchDestroy(mock *robotmock.Mock) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		for _, rs := range state.RootModule().Resources {
+			if rs.Type != "hetznerrobot_vswitch" {
+				continue
+			}
+			id, err := strconv.Atoi(rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+			if vSwitch, ok := mock.VSwitch(id); ok && !vSwitch.Canceled {
+				return fmt.Errorf("vSwitch %d still exists", id)
+			}
+		}
+		return nil
+	}
+}
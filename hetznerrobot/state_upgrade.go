@@ -0,0 +1,190 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	frameworkschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Resources record the version of their schema in the state. Whenever a
+// release changes what a resource stores, its version is raised and a
+// StateUpgrader rewrites states of the previous version, so existing states
+// keep planning cleanly. Plugin framework resources declare theirs in
+// UpgradeState. Fixtures of old states live in testdata/state.
+
+// withStateUpgraderV0 versions r and upgrades states written before
+// resources were versioned, which have the attributes of v0.
+func withStateUpgraderV0(r *schema.Resource, v0 *schema.Resource, upgrade schema.StateUpgradeFunc) *schema.Resource {
+	r.SchemaVersion = 1
+	r.StateUpgraders = []schema.StateUpgrader{{
+		Version: 0,
+		Type:    v0.CoreConfigSchema().ImpliedType(),
+		Upgrade: upgrade,
+	}}
+	return r
+}
+
+// The V0 schemas below are frozen copies of the schemas of the last
+// unversioned release. They only describe the shape of version 0 states and
+// must not change along with the resources.
+
+func resourceBootV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"server_id":        {Type: schema.TypeInt, Required: true},
+			"active_profile":   {Type: schema.TypeString, Optional: true},
+			"architecture":     {Type: schema.TypeString, Optional: true},
+			"language":         {Type: schema.TypeString, Optional: true},
+			"operating_system": {Type: schema.TypeString, Optional: true},
+			"authorized_keys": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"ipv4_address": {Type: schema.TypeString, Computed: true},
+			"ipv6_network": {Type: schema.TypeString, Computed: true},
+			"password":     {Type: schema.TypeString, Computed: true, Sensitive: true},
+		},
+	}
+}
+
+// resourceFirewallSchemaV0 is written for the plugin framework, which the
+// firewall resource moved to later. It has the types of the SDK schema.
+func resourceFirewallSchemaV0() frameworkschema.Schema {
+	stringAttribute := frameworkschema.StringAttribute{Optional: true}
+	return frameworkschema.Schema{
+		Attributes: map[string]frameworkschema.Attribute{
+			"id":            frameworkschema.StringAttribute{Computed: true},
+			"server_ip":     frameworkschema.StringAttribute{Required: true},
+			"active":        frameworkschema.BoolAttribute{Required: true},
+			"whitelist_hos": frameworkschema.BoolAttribute{Required: true},
+		},
+		Blocks: map[string]frameworkschema.Block{
+			"rule": frameworkschema.ListNestedBlock{
+				NestedObject: frameworkschema.NestedBlockObject{
+					Attributes: map[string]frameworkschema.Attribute{
+						"name":       stringAttribute,
+						"dst_ip":     stringAttribute,
+						"dst_port":   stringAttribute,
+						"src_ip":     stringAttribute,
+						"src_port":   stringAttribute,
+						"protocol":   stringAttribute,
+						"tcp_flags":  stringAttribute,
+						"action":     frameworkschema.StringAttribute{Required: true},
+						"ip_version": stringAttribute,
+					},
+				},
+			},
+		},
+	}
+}
+
+type firewallResourceModelV0 struct {
+	ID           types.String        `tfsdk:"id"`
+	ServerIP     types.String        `tfsdk:"server_ip"`
+	Active       types.Bool          `tfsdk:"active"`
+	WhitelistHOS types.Bool          `tfsdk:"whitelist_hos"`
+	Rules        []firewallRuleModel `tfsdk:"rule"`
+}
+
+func resourceVSwitchV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":        {Type: schema.TypeString, Optional: true},
+			"vlan":        {Type: schema.TypeInt, Optional: true},
+			"is_canceled": {Type: schema.TypeBool, Computed: true},
+			"servers": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"server_number":   {Type: schema.TypeInt, Required: true},
+						"server_ip":       {Type: schema.TypeString, Computed: true},
+						"server_ipv6_net": {Type: schema.TypeString, Computed: true},
+						"status":          {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+			"subnets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip":      {Type: schema.TypeString, Required: true},
+						"mask":    {Type: schema.TypeInt, Required: true},
+						"gateway": {Type: schema.TypeString, Required: true},
+					},
+				},
+			},
+			"cloud_networks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":      {Type: schema.TypeInt, Required: true},
+						"ip":      {Type: schema.TypeString, Required: true},
+						"mask":    {Type: schema.TypeInt, Required: true},
+						"gateway": {Type: schema.TypeString, Required: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourceBootStateUpgradeV0 sets server_id of imported boot configurations.
+// The import used to store the ID as string in the integer attribute, which
+// failed silently and left server_id unset. IDs which are no server number,
+// e.g. of configurations imported by server IP, are kept as they are and
+// Read fills in server_id.
+func resourceBootStateUpgradeV0(_ context.Context, rawState map[string]any, _ any) (map[string]any, error) {
+	if serverID, _ := rawState["server_id"].(float64); serverID != 0 {
+		return rawState, nil
+	}
+
+	id, _ := rawState["id"].(string)
+	if serverNumber, err := strconv.Atoi(id); err == nil {
+		rawState["server_id"] = serverNumber
+	}
+	return rawState, nil
+}
+
+// resourceFirewallStateUpgradeV0 canonicalizes firewall rules stored as Robot
+// returned them, and sets filter_ipv6 and port, which version 0 did not
+// have, to their defaults.
+func resourceFirewallStateUpgradeV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior firewallResourceModelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := firewallResourceModel{
+		ID:           prior.ID,
+		ServerIP:     prior.ServerIP,
+		Active:       prior.Active,
+		WhitelistHOS: prior.WhitelistHOS,
+		FilterIPv6:   types.BoolValue(false),
+		Port:         types.StringValue(firewallPortMain),
+	}
+	if prior.Rules != nil {
+		rules := make([]HetznerRobotFirewallRule, 0, len(prior.Rules))
+		for _, rule := range prior.Rules {
+			rules = append(rules, rule.rule())
+		}
+		state.Rules = firewallRuleModels(canonicalFirewallRules(rules))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// resourceVSwitchStateUpgradeV0 sets cancellation_date, which version 0 did
+// not have, to its default.
+func resourceVSwitchStateUpgradeV0(_ context.Context, rawState map[string]any, _ any) (map[string]any, error) {
+	rawState["cancellation_date"] = vSwitchCancellationNow
+	return rawState, nil
+}
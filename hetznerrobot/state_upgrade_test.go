@@ -0,0 +1,108 @@
+package hetznerrobot
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// stateFixture is a resource instance as stored in a Terraform state file.
+type stateFixture struct {
+	SchemaVersion int64           `json:"schema_version"`
+	Attributes    json.RawMessage `json:"attributes"`
+}
+
+// TestStateUpgrade upgrades the old states in testdata/state/<resource type>
+// through the provider server and compares them with the .golden.json file
+// next to them.
+func TestStateUpgrade(t *testing.T) {
+	ctx := context.Background()
+	providerServer, err := ProviderServer(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server := providerServer()
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "state", "*", "*.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	upgraded := map[string]map[int64]bool{}
+	for _, fixture := range fixtures {
+		if strings.HasSuffix(fixture, ".golden.json") {
+			continue
+		}
+		typeName := filepath.Base(filepath.Dir(fixture))
+		var state stateFixture
+		readStateFixture(t, fixture, &state)
+		if upgraded[typeName] == nil {
+			upgraded[typeName] = map[int64]bool{}
+		}
+		upgraded[typeName][state.SchemaVersion] = true
+
+		t.Run(typeName+"/"+strings.TrimSuffix(filepath.Base(fixture), ".json"), func(t *testing.T) {
+			resourceSchema, ok := schemas.ResourceSchemas[typeName]
+			if !ok {
+				t.Fatalf("Unknown resource type %s", typeName)
+			}
+
+			resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: typeName,
+				Version:  state.SchemaVersion,
+				RawState: &tfprotov6.RawState{JSON: state.Attributes},
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, diagnostic := range resp.Diagnostics {
+				t.Fatalf("Unexpected diagnostic: %s: %s", diagnostic.Summary, diagnostic.Detail)
+			}
+
+			var golden json.RawMessage
+			readStateFixture(t, strings.TrimSuffix(fixture, ".json")+".golden.json", &golden)
+			want, err := (&tfprotov6.RawState{JSON: golden}).Unmarshal(resourceSchema.ValueType())
+			if err != nil {
+				t.Fatalf("Unable to decode golden state: %v", err)
+			}
+			got, err := resp.UpgradedState.Unmarshal(resourceSchema.ValueType())
+			if err != nil {
+				t.Fatalf("Unable to decode upgraded state: %v", err)
+			}
+			if !got.Equal(want) {
+				diffs, _ := got.Diff(want)
+				for _, diff := range diffs {
+					t.Errorf("%s: got %v, expected %v", diff.Path, diff.Value1, diff.Value2)
+				}
+			}
+		})
+	}
+
+	for typeName, resourceSchema := range schemas.ResourceSchemas {
+		for version := int64(0); version < resourceSchema.Version; version++ {
+			if !upgraded[typeName][version] {
+				t.Errorf("Expected a state fixture of %s with schema version %d", typeName, version)
+			}
+		}
+	}
+}
+
+func readStateFixture(t *testing.T, path string, v any) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("Unable to decode %s: %v", path, err)
+	}
+}
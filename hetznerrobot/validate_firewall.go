@@ -0,0 +1,270 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// https://robot.your-server.de/doc/webservice/en.html#post-firewall-server-id
+
+// firewallMaxInputRules is the number of input rules Robot accepts per server.
+const firewallMaxInputRules = 10
+
+var firewallProtocols = []string{"tcp", "udp", "gre", "icmp", "ipip", "ah", "esp"}
+
+var firewallTCPFlags = []string{"syn", "fin", "rst", "psh", "urg", "ack"}
+
+// Empty values are accepted by the validators below, as they are sent to Robot
+// like unset ones.
+
+var validateFirewallProtocol = validation.ToDiagFunc(validation.Any(
+	validation.StringIsEmpty,
+	validation.StringInSlice(firewallProtocols, false),
+))
+
+func validateFirewallIP(v any, path cty.Path) diag.Diagnostics {
+	value, ok := v.(string)
+	if !ok {
+		return diag.Errorf("expected type of %v to be string", v)
+	}
+	if value == "" {
+		return nil
+	}
+	if _, err := parseFirewallPrefix(value); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("Invalid IP address or CIDR %q", value),
+			Detail:        err.Error(),
+			AttributePath: path,
+		}}
+	}
+	return nil
+}
+
+func validateFirewallPort(v any, path cty.Path) diag.Diagnostics {
+	value, ok := v.(string)
+	if !ok {
+		return diag.Errorf("expected type of %v to be string", v)
+	}
+	if value == "" {
+		return nil
+	}
+	if err := checkFirewallPort(value); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("Invalid port or port range %q", value),
+			Detail:        err.Error(),
+			AttributePath: path,
+		}}
+	}
+	return nil
+}
+
+// The validators below check the same values for the plugin framework.
+
+// firewallStringValidator reports configured values for which check fails.
+type firewallStringValidator struct {
+	summary     string
+	description string
+	check       func(value string) error
+}
+
+var _ validator.String = firewallStringValidator{}
+
+var firewallIPValidator = firewallStringValidator{
+	summary:     "Invalid IP address or CIDR",
+	description: "value must be an IP address or CIDR",
+	check: optionalFirewallValue(func(value string) error {
+		_, err := parseFirewallPrefix(value)
+		return err
+	}),
+}
+
+var firewallPortValidator = firewallStringValidator{
+	summary:     "Invalid port or port range",
+	description: "value must be a port or port range",
+	check:       optionalFirewallValue(checkFirewallPort),
+}
+
+var firewallTCPFlagsValidator = firewallStringValidator{
+	summary:     "Invalid tcp_flags",
+	description: "value must be TCP flags separated by '|' or '&'",
+	check:       optionalFirewallValue(checkFirewallTCPFlags),
+}
+
+var firewallProtocolValidator = firewallStringValidator{
+	summary:     "Invalid protocol",
+	description: "value must be one of " + strings.Join(firewallProtocols, ", "),
+	check:       optionalFirewallValue(checkFirewallOneOf(firewallProtocols)),
+}
+
+var firewallTimeoutValidator = firewallStringValidator{
+	summary:     "Invalid duration",
+	description: "value must be a positive duration such as \"30s\" or \"2m\"",
+	check: func(value string) error {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		if duration <= 0 {
+			return errors.New("duration must be positive")
+		}
+		return nil
+	},
+}
+
+func firewallOneOfValidator(values ...string) firewallStringValidator {
+	return firewallStringValidator{
+		summary:     "Invalid value",
+		description: "value must be one of " + strings.Join(values, ", "),
+		check:       checkFirewallOneOf(values),
+	}
+}
+
+func (v firewallStringValidator) Description(context.Context) string {
+	return v.description
+}
+
+func (v firewallStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v firewallStringValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	value := req.ConfigValue.ValueString()
+	if err := v.check(value); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, fmt.Sprintf("%s %q", v.summary, value), err.Error())
+	}
+}
+
+// optionalFirewallValue accepts empty values in addition to those accepted
+// by check.
+func optionalFirewallValue(check func(string) error) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		return check(value)
+	}
+}
+
+func checkFirewallOneOf(values []string) func(string) error {
+	return func(value string) error {
+		if !slices.Contains(values, value) {
+			return fmt.Errorf("expected one of %s", strings.Join(values, ", "))
+		}
+		return nil
+	}
+}
+
+// parseFirewallPrefix accepts a single address or a CIDR, as Robot does.
+func parseFirewallPrefix(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		if prefix != prefix.Masked() {
+			return netip.Prefix{}, fmt.Errorf("%s has host bits set, use %s", value, prefix.Masked())
+		}
+		return prefix, nil
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// checkFirewallPort accepts "<port>" or "<from>-<to>".
+func checkFirewallPort(value string) error {
+	from, to, isRange := strings.Cut(value, "-")
+	start, err := parseFirewallPortNumber(from)
+	if err != nil {
+		return err
+	}
+	if !isRange {
+		return nil
+	}
+	end, err := parseFirewallPortNumber(to)
+	if err != nil {
+		return err
+	}
+	if start > end {
+		return fmt.Errorf("range start %d is greater than range end %d", start, end)
+	}
+	return nil
+}
+
+func parseFirewallPortNumber(value string) (int, error) {
+	if value == "" {
+		return 0, errors.New("port must not be empty")
+	}
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 0 || port > 65535 || strings.HasPrefix(value, "+") {
+		return 0, fmt.Errorf("%q is not a port between 0 and 65535", value)
+	}
+	return port, nil
+}
+
+// checkFirewallTCPFlags accepts flags combined with "|" (or) and "&" (and),
+// e.g. "syn", "syn|fin" or "syn&ack".
+func checkFirewallTCPFlags(value string) error {
+	flags := strings.FieldsFunc(value, func(r rune) bool { return r == '|' || r == '&' })
+	if len(flags) == 0 || strings.Count(value, "|")+strings.Count(value, "&") != len(flags)-1 {
+		return errors.New("expected flags separated by '|' or '&', e.g. syn|fin")
+	}
+	for _, flag := range flags {
+		if !slices.Contains(firewallTCPFlags, flag) {
+			return fmt.Errorf("unknown flag %q, expected one of %s", flag, strings.Join(firewallTCPFlags, ", "))
+		}
+	}
+	return nil
+}
+
+// checkFirewallRules validates constraints spanning several rule fields or
+// rules, which cannot be expressed as attribute validators.
+func checkFirewallRules(rules []HetznerRobotFirewallRule) error {
+	if len(rules) > firewallMaxInputRules {
+		return fmt.Errorf("at most %d input rules are supported by Robot, got %d", firewallMaxInputRules, len(rules))
+	}
+
+	for idx, rule := range rules {
+		for _, address := range []struct{ field, value string }{{"src_ip", rule.SrcIP}, {"dst_ip", rule.DstIP}} {
+			field, value := address.field, address.value
+			if value == "" {
+				continue
+			}
+			// Robot rejects addresses on IPv6 rules instead of filtering by them
+			if rule.IPVersion == "ipv6" {
+				return fmt.Errorf("rule %d (%q): %s is not supported with ip_version ipv6", idx, rule.Name, field)
+			}
+			prefix, err := parseFirewallPrefix(value)
+			if err != nil {
+				// reported by the attribute validator
+				continue
+			}
+			if !prefix.Addr().Is4() {
+				return fmt.Errorf("rule %d (%q): %s %s is an IPv6 address but ip_version is ipv4", idx, rule.Name, field, value)
+			}
+		}
+
+		if rule.TCPFlags != "" && rule.Protocol != "tcp" {
+			return fmt.Errorf("rule %d (%q): tcp_flags requires protocol tcp", idx, rule.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package hetznerrobot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestValidateFirewallIP(t *testing.T) {
+	tests := []struct {
+		value   string
+		isValid bool
+	}{
+		{"1.2.3.4", true},
+		{"10.0.0.0/8", true},
+		{"0.0.0.0/0", true},
+		{"2001:db8::/32", true},
+		{"2001:db8::1", true},
+		{"10.0.0.1/8", false},
+		{"10.0.0.0/33", false},
+		{"10.0.0", false},
+		{"example.com", false},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			diags := validateFirewallIP(tt.value, cty.Path{})
+			if tt.isValid && diags.HasError() {
+				t.Fatalf("Expected %q to be valid, got: %v", tt.value, diags)
+			}
+			if !tt.isValid && !diags.HasError() {
+				t.Fatalf("Expected %q to be invalid", tt.value)
+			}
+		})
+	}
+}
+
+func TestValidateFirewallPort(t *testing.T) {
+	tests := []struct {
+		value   string
+		isValid bool
+	}{
+		{"22", true},
+		{"0", true},
+		{"65535", true},
+		{"1024-65535", true},
+		{"80-80", true},
+		{"22-", false},
+		{"-22", false},
+		{"443-80", false},
+		{"65536", false},
+		{"+22", false},
+		{"ssh", false},
+		{"22,80", false},
+		{"1-2-3", false},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			diags := validateFirewallPort(tt.value, cty.Path{})
+			if tt.isValid && diags.HasError() {
+				t.Fatalf("Expected %q to be valid, got: %v", tt.value, diags)
+			}
+			if !tt.isValid && !diags.HasError() {
+				t.Fatalf("Expected %q to be invalid", tt.value)
+			}
+		})
+	}
+}
+
+func TestValidateFirewallProtocol(t *testing.T) {
+	tests := []struct {
+		value   string
+		isValid bool
+	}{
+		{"tcp", true},
+		{"udp", true},
+		{"gre", true},
+		{"icmp", true},
+		{"ipip", true},
+		{"ah", true},
+		{"esp", true},
+		{"TCP", false},
+		{"sctp", false},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			diags := validateFirewallProtocol(tt.value, cty.Path{})
+			if tt.isValid && diags.HasError() {
+				t.Fatalf("Expected %q to be valid, got: %v", tt.value, diags)
+			}
+			if !tt.isValid && !diags.HasError() {
+				t.Fatalf("Expected %q to be invalid", tt.value)
+			}
+		})
+	}
+}
+
+func TestCheckFirewallRules(t *testing.T) {
+	rule := func(ipVersion string, srcIP string, protocol string, tcpFlags string) HetznerRobotFirewallRule {
+		return HetznerRobotFirewallRule{
+			Name:      "test",
+			IPVersion: ipVersion,
+			SrcIP:     srcIP,
+			Protocol:  protocol,
+			TCPFlags:  tcpFlags,
+		}
+	}
+	rules := func(n int) []HetznerRobotFirewallRule {
+		result := make([]HetznerRobotFirewallRule, 0, n)
+		for range n {
+			result = append(result, rule("ipv4", "", "tcp", ""))
+		}
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		rules     []HetznerRobotFirewallRule
+		expectErr string
+	}{
+		{
+			name:  "no rules",
+			rules: []HetznerRobotFirewallRule{},
+		},
+		{
+			name:  "rule limit",
+			rules: rules(firewallMaxInputRules),
+		},
+		{
+			name:      "over rule limit",
+			rules:     rules(firewallMaxInputRules + 1),
+			expectErr: "at most 10 input rules",
+		},
+		{
+			name:  "ipv4 cidr with ipv4",
+			rules: []HetznerRobotFirewallRule{rule("ipv4", "10.0.0.0/8", "", "")},
+		},
+		{
+			name:  "ipv6 without addresses",
+			rules: []HetznerRobotFirewallRule{rule("ipv6", "", "tcp", "")},
+		},
+		{
+			name:      "ipv6 cidr with ipv6",
+			rules:     []HetznerRobotFirewallRule{rule("ipv6", "2001:db8::/32", "", "")},
+			expectErr: "src_ip is not supported with ip_version ipv6",
+		},
+		{
+			name: "dst_ip with ipv6",
+			rules: []HetznerRobotFirewallRule{{
+				Name:      "test",
+				IPVersion: "ipv6",
+				DstIP:     "2001:db8::1/128",
+			}},
+			expectErr: "dst_ip is not supported with ip_version ipv6",
+		},
+		{
+			name:      "ipv6 cidr with ipv4",
+			rules:     []HetznerRobotFirewallRule{rule("ipv4", "2001:db8::/32", "", "")},
+			expectErr: "is an IPv6 address but ip_version is ipv4",
+		},
+		{
+			name:      "ipv4 cidr with ipv6",
+			rules:     []HetznerRobotFirewallRule{rule("ipv6", "10.0.0.0/8", "", "")},
+			expectErr: "src_ip is not supported with ip_version ipv6",
+		},
+		{
+			name:  "tcp flags with tcp",
+			rules: []HetznerRobotFirewallRule{rule("ipv4", "", "tcp", "syn")},
+		},
+		{
+			name:      "tcp flags with udp",
+			rules:     []HetznerRobotFirewallRule{rule("ipv4", "", "udp", "syn")},
+			expectErr: "tcp_flags requires protocol tcp",
+		},
+		{
+			name:      "tcp flags without protocol",
+			rules:     []HetznerRobotFirewallRule{rule("ipv4", "", "", "syn")},
+			expectErr: "tcp_flags requires protocol tcp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFirewallRules(tt.rules)
+			if tt.expectErr == "" && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.expectErr != "" {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				if !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("Expected error containing '%s', got: %v", tt.expectErr, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package robotmock
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+var (
+	bootTypes         = []string{"rescue", "linux"}
+	rescueSystems     = []string{"linux", "vkvm"}
+	linuxDists        = []string{"Debian 12 base", "Ubuntu 22.04 LTS base", "Ubuntu 24.04 LTS base"}
+	linuxLanguages    = []string{"en"}
+	bootArchitectures = []int{64}
+)
+
+// Boot is the active boot configuration of a server.
+type Boot struct {
+	// Type is "rescue" or "linux", or empty if no configuration is active.
+	Type           string
+	OS             string
+	Arch           int
+	Lang           string
+	Password       string
+	AuthorizedKeys []string
+}
+
+// Boot returns the active boot configuration of a server.
+func (m *Mock) Boot(serverNumber int) Boot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if server := m.findServer(strconv.Itoa(serverNumber)); server != nil {
+		return server.boot
+	}
+	return Boot{}
+}
+
+// SetBoot activates a boot configuration on a server without validating
+// it, e.g. to start from a server that is already in the rescue system.
+func (m *Mock) SetBoot(serverNumber int, boot Boot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if server := m.findServer(strconv.Itoa(serverNumber)); server != nil {
+		server.boot = boot
+	}
+}
+
+// bootJSON renders one boot configuration type of a server. Inactive
+// configurations list the available options instead of the chosen ones.
+func (m *Mock) bootJSON(server *serverState, bootType string) map[string]any {
+	result := map[string]any{
+		"server_ip":       server.IP,
+		"server_ipv6_net": server.IPv6Net,
+		"server_number":   server.Number,
+		"active":          false,
+		"password":        nil,
+		"authorized_key":  []any{},
+		"host_key":        []any{},
+		"arch":            bootArchitectures,
+	}
+	switch bootType {
+	case "rescue":
+		result["os"] = rescueSystems
+		result["boot_time"] = nil
+	case "linux":
+		result["dist"] = linuxDists
+		result["lang"] = linuxLanguages
+	}
+
+	boot := server.boot
+	if boot.Type != bootType {
+		return result
+	}
+
+	authorizedKeys := make([]map[string]any, 0, len(boot.AuthorizedKeys))
+	for _, fingerprint := range boot.AuthorizedKeys {
+		if key := m.findKey(fingerprint); key != nil {
+			authorizedKeys = append(authorizedKeys, map[string]any{"key": key})
+		}
+	}
+	result["active"] = true
+	result["password"] = boot.Password
+	result["authorized_key"] = authorizedKeys
+	result["arch"] = boot.Arch
+	switch bootType {
+	case "rescue":
+		result["os"] = boot.OS
+	case "linux":
+		result["dist"] = boot.OS
+		result["lang"] = boot.Lang
+	}
+	return result
+}
+
+func (m *Mock) routeBoot() {
+	m.handle("GET /boot/{id}", func(w http.ResponseWriter, r *http.Request) {
+		server := m.findServer(r.PathValue("id"))
+		if server == nil {
+			writeNotFound(w, "SERVER_NOT_FOUND", "Server %s not found", r.PathValue("id"))
+			return
+		}
+		boot := map[string]any{"vnc": nil, "windows": nil, "plesk": nil, "cpanel": nil}
+		for _, bootType := range bootTypes {
+			boot[bootType] = m.bootJSON(server, bootType)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"boot": boot})
+	})
+
+	m.handle("GET /boot/{id}/{type}", func(w http.ResponseWriter, r *http.Request) {
+		server, bootType, ok := m.bootRequest(w, r)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{bootType: m.bootJSON(server, bootType)})
+	})
+
+	m.handle("POST /boot/{id}/{type}", func(w http.ResponseWriter, r *http.Request) {
+		server, bootType, ok := m.bootRequest(w, r)
+		if !ok {
+			return
+		}
+		switch server.boot.Type {
+		case "":
+		case bootType:
+			writeError(w, http.StatusConflict, "BOOT_ALREADY_ENABLED", "Boot configuration already enabled")
+			return
+		default:
+			writeError(w, http.StatusConflict, "BOOT_BLOCKED", "Boot configuration is blocked because "+server.boot.Type+" is active")
+			return
+		}
+
+		boot := Boot{Type: bootType, Lang: r.PostForm.Get("lang")}
+		options := rescueSystems
+		boot.OS = r.PostForm.Get("os")
+		if bootType == "linux" {
+			options = linuxDists
+			boot.OS = r.PostForm.Get("dist")
+			if boot.Lang == "" || !slices.Contains(linuxLanguages, boot.Lang) {
+				writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid lang")
+				return
+			}
+		}
+		if !slices.Contains(options, boot.OS) {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid operating system")
+			return
+		}
+
+		boot.Arch = 64
+		if arch := r.PostForm.Get("arch"); arch != "" {
+			var err error
+			if boot.Arch, err = strconv.Atoi(arch); err != nil || !slices.Contains(bootArchitectures, boot.Arch) {
+				writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid arch")
+				return
+			}
+		}
+
+		for _, fingerprint := range r.PostForm["authorized_key"] {
+			if m.findKey(fingerprint) == nil {
+				writeError(w, http.StatusBadRequest, "INVALID_INPUT", "unknown authorized_key "+fingerprint)
+				return
+			}
+			boot.AuthorizedKeys = append(boot.AuthorizedKeys, fingerprint)
+		}
+		// Robot only returns a password if no key is given
+		if len(boot.AuthorizedKeys) == 0 {
+			boot.Password = newPassword()
+		}
+
+		server.boot = boot
+		writeJSON(w, http.StatusOK, map[string]any{bootType: m.bootJSON(server, bootType)})
+	})
+
+	m.handle("DELETE /boot/{id}/{type}", func(w http.ResponseWriter, r *http.Request) {
+		server, bootType, ok := m.bootRequest(w, r)
+		if !ok {
+			return
+		}
+		if server.boot.Type == bootType {
+			server.boot = Boot{}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{bootType: m.bootJSON(server, bootType)})
+	})
+}
+
+func (m *Mock) bootRequest(w http.ResponseWriter, r *http.Request) (*serverState, string, bool) {
+	server := m.findServer(r.PathValue("id"))
+	if server == nil {
+		writeNotFound(w, "SERVER_NOT_FOUND", "Server %s not found", r.PathValue("id"))
+		return nil, "", false
+	}
+	bootType := r.PathValue("type")
+	if !slices.Contains(bootTypes, bootType) {
+		writeNotFound(w, "NOT_FOUND", "Boot configuration %s not supported", bootType)
+		return nil, "", false
+	}
+	return server, bootType, true
+}
+
+func newPassword() string {
+	buf := make([]byte, 10)
+	_, _ = rand.Read(buf)
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)
+}
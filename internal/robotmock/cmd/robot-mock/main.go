@@ -0,0 +1,58 @@
+// Command robot-mock serves the fake Robot webservice of package robotmock,
+// for local Terraform runs against the provider:
+//
+//	go run ./internal/robotmock/cmd/robot-mock -seed servers.json
+//
+// and point the provider at it with url = "http://127.0.0.1:8080".
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/robotmock"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8080", "address to listen on")
+	username := flag.String("username", "robot", "webservice username")
+	password := flag.String("password", "robot", "webservice password")
+	settlePolls := flag.Int("settle-polls", robotmock.DefaultSettlePolls, "GET requests until firewall and vSwitch changes leave the \"in process\" state")
+	seed := flag.String("seed", "", "JSON file with the initial servers, keys, firewalls and rdns entries")
+	rateLimits := flag.Bool("rate-limits", false, "enforce the documented Robot request limits")
+	flag.Parse()
+
+	config := robotmock.Config{
+		Username:    *username,
+		Password:    *password,
+		SettlePolls: *settlePolls,
+	}
+	if *rateLimits {
+		config.RateLimits = robotmock.DefaultRateLimits()
+	}
+	mock := robotmock.New(config)
+
+	if *seed != "" {
+		file, err := os.Open(*seed)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = mock.LoadSeed(file)
+		file.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	log.Printf("serving the Robot webservice fake on http://%s", *listen)
+	log.Fatal(http.ListenAndServe(*listen, logRequests(mock)))
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("%s %s", r.Method, r.URL.Path)
+		next.ServeHTTP(w, r)
+	})
+}
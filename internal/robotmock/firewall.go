@@ -0,0 +1,261 @@
+package robotmock
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Firewall is the configuration of a server firewall.
+type Firewall struct {
+	Status       string         `json:"status"`
+	FilterIPv6   bool           `json:"filter_ipv6"`
+	WhitelistHOS bool           `json:"whitelist_hos"`
+	Port         string         `json:"port"`
+	Input        []FirewallRule `json:"input"`
+	Output       []FirewallRule `json:"output"`
+}
+
+// FirewallRule is one input or output rule of a server firewall.
+type FirewallRule struct {
+	IPVersion string `json:"ip_version"`
+	Name      string `json:"name"`
+	DstIP     string `json:"dst_ip"`
+	SrcIP     string `json:"src_ip"`
+	DstPort   string `json:"dst_port"`
+	SrcPort   string `json:"src_port"`
+	Protocol  string `json:"protocol"`
+	TCPFlags  string `json:"tcp_flags"`
+	Action    string `json:"action"`
+}
+
+// firewallState holds the applied firewall and a pending change, which is
+// applied once polls reaches zero.
+type firewallState struct {
+	applied Firewall
+	pending *Firewall
+	polls   int
+}
+
+func newFirewallState() *firewallState {
+	return &firewallState{applied: Firewall{Status: "disabled", Port: "main"}}
+}
+
+// Firewall returns the firewall of a server as it was last applied.
+func (m *Mock) Firewall(serverNumber int) Firewall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if firewall, ok := m.firewalls[serverNumber]; ok {
+		return firewall.applied
+	}
+	return Firewall{}
+}
+
+// SetFirewall applies a firewall configuration to a server right away.
+func (m *Mock) SetFirewall(serverNumber int, firewall Firewall) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if state, ok := m.firewalls[serverNumber]; ok {
+		state.applied, state.pending = firewall, nil
+	}
+}
+
+// poll counts a GET request and applies the pending change once enough
+// polls were seen.
+func (f *firewallState) poll() {
+	if f.pending == nil {
+		return
+	}
+	f.polls--
+	if f.polls <= 0 {
+		f.applied, f.pending = *f.pending, nil
+	}
+}
+
+func firewallJSON(server *serverState, firewall *firewallState) map[string]any {
+	// Robot shows a pending change right away, only the status tells it
+	// is not active yet
+	current := firewall.applied
+	status := current.Status
+	if firewall.pending != nil {
+		current, status = *firewall.pending, "in process"
+	}
+	rules := func(rules []FirewallRule) []FirewallRule {
+		if rules == nil {
+			return []FirewallRule{}
+		}
+		return rules
+	}
+	return map[string]any{"firewall": map[string]any{
+		"server_ip":     server.IP,
+		"server_number": server.Number,
+		"status":        status,
+		"filter_ipv6":   current.FilterIPv6,
+		"whitelist_hos": current.WhitelistHOS,
+		"port":          current.Port,
+		"rules": map[string]any{
+			"input":  rules(current.Input),
+			"output": rules(current.Output),
+		},
+	}}
+}
+
+var firewallRuleField = regexp.MustCompile(`^rules\[(input|output)\]\[(\d+)\]\[([a-z_]+)\]$`)
+
+// parseFirewallRules reads rules[input][0][name]=... style form fields, in
+// the order of their indices.
+func parseFirewallRules(form map[string][]string) (input, output []FirewallRule, message string) {
+	type indexedRule struct {
+		index int
+		rule  FirewallRule
+	}
+	rules := map[string]map[int]*FirewallRule{"input": {}, "output": {}}
+	for field, values := range form {
+		match := firewallRuleField.FindStringSubmatch(field)
+		if match == nil {
+			if strings.HasPrefix(field, "rules") {
+				return nil, nil, "invalid rule field " + field
+			}
+			continue
+		}
+		index, _ := strconv.Atoi(match[2])
+		rule, ok := rules[match[1]][index]
+		if !ok {
+			rule = &FirewallRule{}
+			rules[match[1]][index] = rule
+		}
+		value := values[0]
+		switch match[3] {
+		case "ip_version":
+			rule.IPVersion = value
+		case "name":
+			rule.Name = value
+		case "dst_ip":
+			rule.DstIP = value
+		case "src_ip":
+			rule.SrcIP = value
+		case "dst_port":
+			rule.DstPort = value
+		case "src_port":
+			rule.SrcPort = value
+		case "protocol":
+			rule.Protocol = value
+		case "tcp_flags":
+			rule.TCPFlags = value
+		case "action":
+			rule.Action = value
+		default:
+			return nil, nil, "invalid rule field " + field
+		}
+	}
+
+	sorted := func(direction string) ([]FirewallRule, string) {
+		indexed := make([]indexedRule, 0, len(rules[direction]))
+		for index, rule := range rules[direction] {
+			if rule.Action != "accept" && rule.Action != "discard" {
+				return nil, "invalid action in " + direction + " rule " + strconv.Itoa(index)
+			}
+			if rule.IPVersion == "ipv6" && (rule.SrcIP != "" || rule.DstIP != "") {
+				return nil, "IPv6 rules must not set src_ip or dst_ip"
+			}
+			indexed = append(indexed, indexedRule{index: index, rule: *rule})
+		}
+		slices.SortFunc(indexed, func(a, b indexedRule) int { return a.index - b.index })
+		result := make([]FirewallRule, 0, len(indexed))
+		for _, entry := range indexed {
+			result = append(result, entry.rule)
+		}
+		return result, ""
+	}
+
+	if input, message = sorted("input"); message != "" {
+		return nil, nil, message
+	}
+	if output, message = sorted("output"); message != "" {
+		return nil, nil, message
+	}
+	return input, output, ""
+}
+
+func (m *Mock) routeFirewall() {
+	m.handle("GET /firewall/{id}", func(w http.ResponseWriter, r *http.Request) {
+		server := m.findServer(r.PathValue("id"))
+		if server == nil {
+			writeNotFound(w, "SERVER_NOT_FOUND", "Server %s not found", r.PathValue("id"))
+			return
+		}
+		firewall := m.firewalls[server.Number]
+		firewall.poll()
+		writeJSON(w, http.StatusOK, firewallJSON(server, firewall))
+	})
+
+	m.handle("POST /firewall/{id}", func(w http.ResponseWriter, r *http.Request) {
+		server := m.findServer(r.PathValue("id"))
+		if server == nil {
+			writeNotFound(w, "SERVER_NOT_FOUND", "Server %s not found", r.PathValue("id"))
+			return
+		}
+		firewall := m.firewalls[server.Number]
+		if firewall.pending != nil {
+			writeError(w, http.StatusConflict, "FIREWALL_IN_PROCESS", "The firewall cannot be updated while the previous update is in process")
+			return
+		}
+
+		target := Firewall{
+			Status:       r.PostForm.Get("status"),
+			FilterIPv6:   r.PostForm.Get("filter_ipv6") == "true",
+			WhitelistHOS: r.PostForm.Get("whitelist_hos") == "true",
+			Port:         r.PostForm.Get("port"),
+		}
+		if target.Status != "active" && target.Status != "disabled" {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "status must be active or disabled")
+			return
+		}
+		switch target.Port {
+		case "":
+			target.Port = firewall.applied.Port
+		case "main", "kvm":
+		default:
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "port must be main or kvm")
+			return
+		}
+		var message string
+		if target.Input, target.Output, message = parseFirewallRules(r.PostForm); message != "" {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", message)
+			return
+		}
+
+		m.applyFirewall(firewall, target)
+		writeJSON(w, http.StatusAccepted, firewallJSON(server, firewall))
+	})
+
+	m.handle("DELETE /firewall/{id}", func(w http.ResponseWriter, r *http.Request) {
+		server := m.findServer(r.PathValue("id"))
+		if server == nil {
+			writeNotFound(w, "SERVER_NOT_FOUND", "Server %s not found", r.PathValue("id"))
+			return
+		}
+		firewall := m.firewalls[server.Number]
+		if firewall.pending != nil {
+			writeError(w, http.StatusConflict, "FIREWALL_IN_PROCESS", "The firewall cannot be updated while the previous update is in process")
+			return
+		}
+		m.applyFirewall(firewall, newFirewallState().applied)
+		writeJSON(w, http.StatusAccepted, firewallJSON(server, firewall))
+	})
+}
+
+// applyFirewall stages target, or applies it right away if the Mock does
+// not simulate "in process" states.
+func (m *Mock) applyFirewall(firewall *firewallState, target Firewall) {
+	if m.config.SettlePolls <= 0 {
+		firewall.applied = target
+		return
+	}
+	firewall.pending = &target
+	firewall.polls = m.config.SettlePolls
+}
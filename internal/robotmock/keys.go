@@ -0,0 +1,185 @@
+package robotmock
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Key is an SSH public key stored in Robot.
+type Key struct {
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint"`
+	Type        string `json:"type"`
+	Size        int    `json:"size"`
+	Data        string `json:"data"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// AddKey stores an SSH public key in OpenSSH format and returns it with its
+// fingerprint.
+func (m *Mock) AddKey(name string, data string) (Key, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, err := m.addKey(name, data)
+	if err != nil {
+		return Key{}, err
+	}
+	return *key, nil
+}
+
+func (m *Mock) addKey(name string, data string) (*Key, error) {
+	key, err := parseKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if m.findKey(key.Fingerprint) != nil {
+		return nil, errKeyExists
+	}
+	key.Name = name
+	key.CreatedAt = m.config.Now().Format(time.DateTime)
+	m.keys = append(m.keys, key)
+	return key, nil
+}
+
+var errKeyExists = errors.New("key already exists")
+
+func (m *Mock) findKey(fingerprint string) *Key {
+	for _, key := range m.keys {
+		if key.Fingerprint == fingerprint {
+			return key
+		}
+	}
+	return nil
+}
+
+// parseKey reads type, size and fingerprint from an OpenSSH public key.
+func parseKey(data string) (*Key, error) {
+	fields := strings.Fields(data)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("expected <type> <base64 key> [comment]")
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid key data: %w", err)
+	}
+
+	// Robot identifies keys by their MD5 fingerprint
+	sum := md5.Sum(blob)
+	fingerprint := make([]string, 0, len(sum))
+	for _, b := range sum {
+		fingerprint = append(fingerprint, fmt.Sprintf("%02x", b))
+	}
+
+	key := &Key{
+		Fingerprint: strings.Join(fingerprint, ":"),
+		Data:        strings.Join(fields[:2], " "),
+	}
+	switch fields[0] {
+	case "ssh-ed25519":
+		key.Type, key.Size = "ED25519", 256
+	case "ecdsa-sha2-nistp256":
+		key.Type, key.Size = "ECDSA", 256
+	case "ecdsa-sha2-nistp384":
+		key.Type, key.Size = "ECDSA", 384
+	case "ecdsa-sha2-nistp521":
+		key.Type, key.Size = "ECDSA", 521
+	case "ssh-rsa":
+		key.Type = "RSA"
+		key.Size, err = rsaKeySize(blob)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", fields[0])
+	}
+	return key, nil
+}
+
+// rsaKeySize returns the modulus size of an ssh-rsa key blob, which holds
+// the type name, the exponent and the modulus as length prefixed strings.
+func rsaKeySize(blob []byte) (int, error) {
+	var modulus []byte
+	for i := 0; i < 3; i++ {
+		if len(blob) < 4 {
+			return 0, fmt.Errorf("invalid RSA key")
+		}
+		length := binary.BigEndian.Uint32(blob)
+		if uint32(len(blob)-4) < length {
+			return 0, fmt.Errorf("invalid RSA key")
+		}
+		modulus, blob = blob[4:4+length], blob[4+length:]
+	}
+	return new(big.Int).SetBytes(modulus).BitLen(), nil
+}
+
+func (m *Mock) routeKeys() {
+	m.handle("GET /key", func(w http.ResponseWriter, r *http.Request) {
+		if len(m.keys) == 0 {
+			writeNotFound(w, "NOT_FOUND", "No keys found")
+			return
+		}
+		keys := make([]map[string]any, 0, len(m.keys))
+		for _, key := range m.keys {
+			keys = append(keys, map[string]any{"key": key})
+		}
+		writeJSON(w, http.StatusOK, keys)
+	})
+
+	m.handle("POST /key", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PostForm.Get("name")
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "name is required")
+			return
+		}
+		key, err := m.addKey(name, r.PostForm.Get("data"))
+		if errors.Is(err, errKeyExists) {
+			writeError(w, http.StatusConflict, "KEY_ALREADY_EXISTS", "The supplied key already exists")
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]any{"key": key})
+	})
+
+	m.handle("GET /key/{fingerprint}", func(w http.ResponseWriter, r *http.Request) {
+		key := m.findKey(r.PathValue("fingerprint"))
+		if key == nil {
+			writeNotFound(w, "NOT_FOUND", "Key %s not found", r.PathValue("fingerprint"))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"key": key})
+	})
+
+	m.handle("POST /key/{fingerprint}", func(w http.ResponseWriter, r *http.Request) {
+		key := m.findKey(r.PathValue("fingerprint"))
+		if key == nil {
+			writeNotFound(w, "NOT_FOUND", "Key %s not found", r.PathValue("fingerprint"))
+			return
+		}
+		if name := r.PostForm.Get("name"); name != "" {
+			key.Name = name
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"key": key})
+	})
+
+	m.handle("DELETE /key/{fingerprint}", func(w http.ResponseWriter, r *http.Request) {
+		for i, key := range m.keys {
+			if key.Fingerprint == r.PathValue("fingerprint") {
+				m.keys = append(m.keys[:i], m.keys[i+1:]...)
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+		writeNotFound(w, "NOT_FOUND", "Key %s not found", r.PathValue("fingerprint"))
+	})
+}
@@ -0,0 +1,63 @@
+package robotmock
+
+import "net/http"
+
+// MarketProduct is a server offered on the server market (auction). Prices
+// are decimal strings like Robot returns them, e.g. "39.0000".
+type MarketProduct struct {
+	ID             int      `json:"id"`
+	Name           string   `json:"name"`
+	Description    []string `json:"description"`
+	Traffic        string   `json:"traffic"`
+	Dist           []string `json:"dist"`
+	CPU            string   `json:"cpu"`
+	CPUBenchmark   int      `json:"cpu_benchmark"`
+	MemorySize     int      `json:"memory_size"`
+	HDDSize        int      `json:"hdd_size"`
+	HDDText        string   `json:"hdd_text"`
+	HDDCount       int      `json:"hdd_count"`
+	Datacenter     string   `json:"datacenter"`
+	NetworkSpeed   string   `json:"network_speed"`
+	Price          string   `json:"price"`
+	PriceSetup     string   `json:"price_setup"`
+	FixedPrice     bool     `json:"fixed_price"`
+	NextReduce     int      `json:"next_reduce"`
+	NextReduceDate string   `json:"next_reduce_date"`
+}
+
+// AddMarketProduct offers a server on the server market. Unset fields get
+// defaults of a typical offer.
+func (m *Mock) AddMarketProduct(product MarketProduct) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if product.Name == "" {
+		product.Name = "SB"
+	}
+	if product.Description == nil {
+		product.Description = []string{}
+	}
+	if product.Dist == nil {
+		product.Dist = []string{"Rescue system"}
+	}
+	if product.Traffic == "" {
+		product.Traffic = "unlimited"
+	}
+	if product.NetworkSpeed == "" {
+		product.NetworkSpeed = "1 Gbit/s"
+	}
+	if product.PriceSetup == "" {
+		product.PriceSetup = "0.0000"
+	}
+	m.marketProducts = append(m.marketProducts, product)
+}
+
+func (m *Mock) routeMarket() {
+	m.handle("GET /order/server_market/product", func(w http.ResponseWriter, r *http.Request) {
+		products := make([]map[string]any, 0, len(m.marketProducts))
+		for _, product := range m.marketProducts {
+			products = append(products, map[string]any{"product": product})
+		}
+		writeJSON(w, http.StatusOK, products)
+	})
+}
@@ -0,0 +1,217 @@
+// Package robotmock is a stateful fake of the Hetzner Robot webservice for
+// tests and local development. It keeps dedicated servers, boot
+// configurations, firewalls, vSwitches, resets, reverse DNS entries, SSH
+// keys and server market offers in memory and mimics the Robot behaviour
+// the provider relies on: basic authentication, the JSON error envelope,
+// per-endpoint request limits and "in process" states that settle after a
+// few polls.
+//
+// https://robot.your-server.de/doc/webservice/en.html
+package robotmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSettlePolls is the number of GET requests after which firewall
+// changes and vSwitch server attachments leave the "in process" state.
+const DefaultSettlePolls = 2
+
+// Config configures a Mock.
+type Config struct {
+	// Username and Password are the credentials of the webservice user.
+	Username string
+	Password string
+	// SettlePolls is the number of GET requests on a firewall or vSwitch
+	// after which a change is applied. Zero applies changes immediately.
+	SettlePolls int
+	// RateLimits are enforced per webservice user. Nil uses no limits.
+	RateLimits []RateLimit
+	// Now returns the current time, for rate limits and cancellation
+	// dates. Nil uses time.Now.
+	Now func() time.Time
+}
+
+// RateLimit allows Max requests per Interval to all paths below Prefix,
+// e.g. /reset. An empty Method matches all methods.
+type RateLimit struct {
+	Method   string
+	Prefix   string
+	Max      int
+	Interval time.Duration
+}
+
+// DefaultRateLimits returns the request limits Robot documents for the
+// endpoints the provider uses.
+func DefaultRateLimits() []RateLimit {
+	return []RateLimit{
+		{Method: http.MethodPost, Prefix: "/reset", Max: 50, Interval: time.Hour},
+		{Prefix: "/reset", Max: 500, Interval: time.Hour},
+		{Prefix: "/boot", Max: 500, Interval: time.Hour},
+		{Prefix: "/firewall", Max: 500, Interval: time.Hour},
+		{Prefix: "/server", Max: 200, Interval: time.Hour},
+		{Prefix: "/vswitch", Max: 500, Interval: time.Hour},
+	}
+}
+
+// Request is a request received by the Mock.
+type Request struct {
+	Method string
+	Path   string
+	Form   url.Values
+}
+
+// Mock is an http.Handler serving the fake Robot webservice.
+type Mock struct {
+	config Config
+	mux    *http.ServeMux
+
+	mu          sync.Mutex
+	requests    []Request
+	rateLimited map[int][]time.Time
+	servers     []*serverState
+	firewalls   map[int]*firewallState
+	vSwitches   []*vSwitchState
+	nextVSwitch int
+	rdns        map[string]string
+	keys        []*Key
+
+	marketProducts []MarketProduct
+}
+
+// New returns a Mock without any servers.
+func New(config Config) *Mock {
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	m := &Mock{
+		config:      config,
+		mux:         http.NewServeMux(),
+		rateLimited: map[int][]time.Time{},
+		firewalls:   map[int]*firewallState{},
+		nextVSwitch: 1,
+		rdns:        map[string]string{},
+	}
+	m.routeServers()
+	m.routeBoot()
+	m.routeReset()
+	m.routeFirewall()
+	m.routeVSwitch()
+	m.routeRDNS()
+	m.routeKeys()
+	m.routeMarket()
+	return m
+}
+
+// ServeHTTP implements http.Handler.
+func (m *Mock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	if !ok || username != m.config.Username || password != m.config.Password {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Unauthorized")
+		return
+	}
+
+	// Robot reads DELETE bodies like POST bodies, which ParseForm ignores
+	if r.Method == http.MethodDelete {
+		r.Method = http.MethodPost
+		err := r.ParseForm()
+		r.Method = http.MethodDelete
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid form body")
+			return
+		}
+	} else if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid form body")
+		return
+	}
+
+	m.mu.Lock()
+	m.requests = append(m.requests, Request{Method: r.Method, Path: r.URL.Path, Form: r.PostForm})
+	limit, exceeded := m.rateLimitExceeded(r.Method, r.URL.Path)
+	m.mu.Unlock()
+	if exceeded {
+		writeJSON(w, http.StatusForbidden, map[string]any{
+			"error": map[string]any{
+				"status":      http.StatusForbidden,
+				"code":        "RATE_LIMIT_EXCEEDED",
+				"message":     "Rate limit exceeded",
+				"max_request": limit.Max,
+				"interval":    int(limit.Interval.Seconds()),
+			},
+		})
+		return
+	}
+
+	m.mux.ServeHTTP(w, r)
+}
+
+// Requests returns all requests received so far, including rejected ones.
+func (m *Mock) Requests() []Request {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Request(nil), m.requests...)
+}
+
+// rateLimitExceeded records a request and reports the first limit it
+// exceeds.
+func (m *Mock) rateLimitExceeded(method string, path string) (RateLimit, bool) {
+	now := m.config.Now()
+	for i, limit := range m.config.RateLimits {
+		if limit.Method != "" && limit.Method != method {
+			continue
+		}
+		if path != limit.Prefix && !strings.HasPrefix(path, limit.Prefix+"/") {
+			continue
+		}
+
+		recent := m.rateLimited[i][:0]
+		for _, at := range m.rateLimited[i] {
+			if now.Sub(at) < limit.Interval {
+				recent = append(recent, at)
+			}
+		}
+		if len(recent) >= limit.Max {
+			m.rateLimited[i] = recent
+			return limit, true
+		}
+		m.rateLimited[i] = append(recent, now)
+	}
+	return RateLimit{}, false
+}
+
+// handle registers a handler that runs with the state locked.
+func (m *Mock) handle(pattern string, handler func(w http.ResponseWriter, r *http.Request)) {
+	m.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		handler(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes Robot's error envelope.
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]any{
+			"status":  status,
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, code string, format string, args ...any) {
+	writeError(w, http.StatusNotFound, code, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,288 @@
+package robotmock
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGb0pUnrSsoU6Ue2ygdajOtzEKPBF7XGr+OIRYMTsiT3 test"
+
+func newTestMock(t *testing.T, config Config) (*Mock, *httptest.Server) {
+	t.Helper()
+
+	config.Username, config.Password = "user", "pass"
+	mock := New(config)
+	mock.AddServer(Server{Number: 1, IP: "1.2.3.4"})
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	return mock, server
+}
+
+// call sends a request and decodes the JSON response, if there is one.
+func call(t *testing.T, server *httptest.Server, method, path string, form url.Values) (int, map[string]any) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	req.SetBasicAuth("user", "pass")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+	var result map[string]any
+	_ = json.Unmarshal(body, &result)
+	return res.StatusCode, result
+}
+
+func errorCode(body map[string]any) string {
+	envelope, _ := body["error"].(map[string]any)
+	code, _ := envelope["code"].(string)
+	return code
+}
+
+func TestMockAuthentication(t *testing.T) {
+	_, server := newTestMock(t, Config{})
+
+	res, err := http.Get(server.URL + "/server")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected status 401, got %d", res.StatusCode)
+	}
+
+	status, body := call(t, server, "GET", "/server/1.2.3.4", nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if number := body["server"].(map[string]any)["server_number"]; number != float64(1) {
+		t.Fatalf("Expected server 1, got %v", number)
+	}
+}
+
+func TestMockErrorEnvelope(t *testing.T) {
+	_, server := newTestMock(t, Config{})
+
+	status, body := call(t, server, "GET", "/server/2", nil)
+	if status != http.StatusNotFound || errorCode(body) != "SERVER_NOT_FOUND" {
+		t.Fatalf("Expected 404 SERVER_NOT_FOUND, got %d %v", status, body)
+	}
+	status, body = call(t, server, "GET", "/unknown", nil)
+	if status != http.StatusNotFound || errorCode(body) != "NOT_FOUND" {
+		t.Fatalf("Expected 404 NOT_FOUND, got %d %v", status, body)
+	}
+}
+
+func TestMockFirewallInProcess(t *testing.T) {
+	mock, server := newTestMock(t, Config{SettlePolls: 2})
+
+	form := url.Values{}
+	form.Set("status", "active")
+	form.Set("whitelist_hos", "true")
+	form.Set("rules[input][1][name]", "HTTPS")
+	form.Set("rules[input][1][dst_port]", "443")
+	form.Set("rules[input][1][action]", "accept")
+	form.Set("rules[input][0][name]", "SSH")
+	form.Set("rules[input][0][dst_port]", "22")
+	form.Set("rules[input][0][action]", "accept")
+	if status, body := call(t, server, "POST", "/firewall/1", form); status != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d %v", status, body)
+	}
+	if status, body := call(t, server, "POST", "/firewall/1", form); errorCode(body) != "FIREWALL_IN_PROCESS" {
+		t.Fatalf("Expected FIREWALL_IN_PROCESS, got %d %v", status, body)
+	}
+
+	statuses := []string{}
+	for i := 0; i < 3; i++ {
+		_, body := call(t, server, "GET", "/firewall/1.2.3.4", nil)
+		statuses = append(statuses, body["firewall"].(map[string]any)["status"].(string))
+	}
+	if strings.Join(statuses, ",") != "in process,active,active" {
+		t.Fatalf("Unexpected status sequence %v", statuses)
+	}
+
+	firewall := mock.Firewall(1)
+	if !firewall.WhitelistHOS || len(firewall.Input) != 2 || firewall.Input[0].Name != "SSH" {
+		t.Fatalf("Unexpected firewall %+v", firewall)
+	}
+
+	form.Set("rules[input][0][action]", "drop")
+	if status, body := call(t, server, "POST", "/firewall/1", form); errorCode(body) != "INVALID_INPUT" {
+		t.Fatalf("Expected INVALID_INPUT, got %d %v", status, body)
+	}
+}
+
+func TestMockVSwitch(t *testing.T) {
+	mock, server := newTestMock(t, Config{SettlePolls: 1})
+	mock.AddServer(Server{Number: 2})
+
+	form := url.Values{"name": {"private"}, "vlan": {"4000"}}
+	status, body := call(t, server, "POST", "/vswitch", form)
+	if status != http.StatusCreated || body["id"] != float64(1) {
+		t.Fatalf("Expected vSwitch 1 to be created, got %d %v", status, body)
+	}
+	if _, body := call(t, server, "POST", "/vswitch", form); errorCode(body) != "VSWITCH_VLAN_NOT_UNIQUE" {
+		t.Fatalf("Expected VSWITCH_VLAN_NOT_UNIQUE, got %v", body)
+	}
+	if _, body := call(t, server, "POST", "/vswitch", url.Values{"name": {"x"}, "vlan": {"100"}}); errorCode(body) != "INVALID_INPUT" {
+		t.Fatalf("Expected INVALID_INPUT, got %v", body)
+	}
+
+	if status, body := call(t, server, "POST", "/vswitch/1/server", url.Values{"server[]": {"1", "2"}}); status != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d %v", status, body)
+	}
+	if _, body := call(t, server, "DELETE", "/vswitch/1/server", url.Values{"server[]": {"2"}}); errorCode(body) != "VSWITCH_IN_PROCESS" {
+		t.Fatalf("Expected VSWITCH_IN_PROCESS, got %v", body)
+	}
+	if vSwitch, _ := mock.VSwitch(1); vSwitch.Servers[2] != "in process" {
+		t.Fatalf("Expected server 2 to be in process, got %v", vSwitch.Servers)
+	}
+	call(t, server, "GET", "/vswitch/1", nil)
+	if vSwitch, _ := mock.VSwitch(1); vSwitch.Servers[2] != "ready" {
+		t.Fatalf("Expected server 2 to be ready, got %v", vSwitch.Servers)
+	}
+
+	if status, body := call(t, server, "DELETE", "/vswitch/1/server", url.Values{"server": {"2"}}); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d %v", status, body)
+	}
+	if status, body := call(t, server, "DELETE", "/vswitch/1", url.Values{"cancellation_date": {"now"}}); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d %v", status, body)
+	}
+	if _, ok := mock.VSwitch(1); ok {
+		t.Fatal("Expected vSwitch 1 to be deleted")
+	}
+}
+
+func TestMockBoot(t *testing.T) {
+	mock, server := newTestMock(t, Config{})
+	key, err := mock.AddKey("test", testKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	form := url.Values{"os": {"linux"}, "authorized_key": {key.Fingerprint}}
+	status, body := call(t, server, "POST", "/boot/1/rescue", form)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d %v", status, body)
+	}
+	if password := body["rescue"].(map[string]any)["password"]; password != "" {
+		t.Fatalf("Expected no password with authorized keys, got %v", password)
+	}
+	if _, body := call(t, server, "POST", "/boot/1/rescue", form); errorCode(body) != "BOOT_ALREADY_ENABLED" {
+		t.Fatalf("Expected BOOT_ALREADY_ENABLED, got %v", body)
+	}
+	linux := url.Values{"dist": {"Debian 12 base"}, "lang": {"en"}}
+	if _, body := call(t, server, "POST", "/boot/1/linux", linux); errorCode(body) != "BOOT_BLOCKED" {
+		t.Fatalf("Expected BOOT_BLOCKED, got %v", body)
+	}
+
+	call(t, server, "DELETE", "/boot/1/rescue", nil)
+	if boot := mock.Boot(1); boot.Type != "" {
+		t.Fatalf("Expected no active boot configuration, got %+v", boot)
+	}
+}
+
+func TestMockKeys(t *testing.T) {
+	_, server := newTestMock(t, Config{})
+
+	status, body := call(t, server, "POST", "/key", url.Values{"name": {"test"}, "data": {testKey}})
+	if status != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d %v", status, body)
+	}
+	key := body["key"].(map[string]any)
+	if key["type"] != "ED25519" || key["size"] != float64(256) {
+		t.Fatalf("Unexpected key %v", key)
+	}
+	if _, body := call(t, server, "POST", "/key", url.Values{"name": {"again"}, "data": {testKey}}); errorCode(body) != "KEY_ALREADY_EXISTS" {
+		t.Fatalf("Expected KEY_ALREADY_EXISTS, got %v", body)
+	}
+	if status, _ := call(t, server, "DELETE", "/key/"+key["fingerprint"].(string), nil); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+}
+
+func TestMockRateLimits(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, server := newTestMock(t, Config{
+		RateLimits: []RateLimit{{Method: http.MethodPost, Prefix: "/reset", Max: 2, Interval: time.Hour}},
+		Now:        func() time.Time { return now },
+	})
+
+	form := url.Values{"type": {"sw"}}
+	for i := 0; i < 2; i++ {
+		if status, body := call(t, server, "POST", "/reset/1", form); status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d %v", status, body)
+		}
+	}
+	status, body := call(t, server, "POST", "/reset/1", form)
+	if status != http.StatusForbidden || errorCode(body) != "RATE_LIMIT_EXCEEDED" {
+		t.Fatalf("Expected 403 RATE_LIMIT_EXCEEDED, got %d %v", status, body)
+	}
+	if status, _ := call(t, server, "GET", "/reset/1", nil); status != http.StatusOK {
+		t.Fatalf("Expected GET to be unaffected, got %d", status)
+	}
+
+	now = now.Add(time.Hour)
+	if status, _ := call(t, server, "POST", "/reset/1", form); status != http.StatusOK {
+		t.Fatalf("Expected the limit to reset after the interval, got %d", status)
+	}
+}
+
+func TestMockLoadSeed(t *testing.T) {
+	mock := New(Config{})
+	seed := `{
+		"servers": [{"server_number": 7, "server_ip": "5.6.7.8"}],
+		"keys": [{"name": "test", "data": "` + testKey + `"}],
+		"firewalls": {"7": {"status": "active", "port": "main", "input": [{"name": "SSH", "dst_port": "22", "action": "accept"}]}},
+		"rdns": {"5.6.7.8": "host.example.com"}
+	}`
+	if err := mock.LoadSeed(strings.NewReader(seed)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if firewall := mock.Firewall(7); firewall.Status != "active" || len(firewall.Input) != 1 {
+		t.Fatalf("Unexpected firewall %+v", firewall)
+	}
+
+	err := New(Config{}).LoadSeed(strings.NewReader(`{"firewalls": {"1": {}}}`))
+	if err == nil {
+		t.Fatal("Expected an error for a firewall of an unknown server")
+	}
+}
+
+func TestMockMarketProducts(t *testing.T) {
+	mock, server := newTestMock(t, Config{})
+	mock.AddMarketProduct(MarketProduct{ID: 7, CPU: "AMD Ryzen 7 3700X", Price: "45.0000"})
+
+	req, err := http.NewRequest("GET", server.URL+"/order/server_market/product", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	req.SetBasicAuth("user", "pass")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer res.Body.Close()
+
+	// the product list is a JSON array, which call does not decode
+	var products []map[string]MarketProduct
+	if err := json.NewDecoder(res.Body).Decode(&products); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(products) != 1 || products[0]["product"].ID != 7 || products[0]["product"].PriceSetup != "0.0000" {
+		t.Fatalf("Unexpected products %+v", products)
+	}
+}
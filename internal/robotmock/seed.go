@@ -0,0 +1,65 @@
+package robotmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Seed is the initial state of a Mock, e.g. read from a JSON file for the
+// robot-mock command.
+type Seed struct {
+	Servers   []Server            `json:"servers"`
+	Keys      []SeedKey           `json:"keys"`
+	Firewalls map[string]Firewall `json:"firewalls"`
+	RDNS      map[string]string   `json:"rdns"`
+	// MarketProducts are offered on the server market.
+	MarketProducts []MarketProduct `json:"server_market_products"`
+}
+
+// SeedKey is an SSH public key in OpenSSH format.
+type SeedKey struct {
+	Name string `json:"name"`
+	Data string `json:"data"`
+}
+
+// LoadSeed reads a JSON encoded Seed and adds its servers, keys, firewalls,
+// reverse DNS entries and server market products. Firewalls are keyed by
+// server number.
+func (m *Mock) LoadSeed(r io.Reader) error {
+	var seed Seed
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&seed); err != nil {
+		return fmt.Errorf("invalid seed: %w", err)
+	}
+
+	for _, server := range seed.Servers {
+		m.AddServer(server)
+	}
+	for _, product := range seed.MarketProducts {
+		m.AddMarketProduct(product)
+	}
+	for _, key := range seed.Keys {
+		if _, err := m.AddKey(key.Name, key.Data); err != nil {
+			return fmt.Errorf("invalid seed key %s: %w", key.Name, err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for serverID, firewall := range seed.Firewalls {
+		server := m.findServer(serverID)
+		if server == nil {
+			return fmt.Errorf("invalid seed firewall: server %s not found", serverID)
+		}
+		m.firewalls[server.Number].applied = firewall
+	}
+	for ip, ptr := range seed.RDNS {
+		if !m.ownsIP(ip) {
+			return fmt.Errorf("invalid seed rdns: IP %s not found", ip)
+		}
+		m.rdns[ip] = ptr
+	}
+	return nil
+}
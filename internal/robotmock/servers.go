@@ -0,0 +1,248 @@
+package robotmock
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Server is a dedicated server known to the Mock.
+type Server struct {
+	Number     int    `json:"server_number"`
+	IP         string `json:"server_ip"`
+	IPv6Net    string `json:"server_ipv6_net"`
+	Name       string `json:"server_name"`
+	Product    string `json:"product"`
+	DataCenter string `json:"dc"`
+	Status     string `json:"status"`
+	Canceled   bool   `json:"canceled"`
+	PaidUntil  string `json:"paid_until"`
+}
+
+type serverState struct {
+	Server
+	boot   Boot
+	resets []string
+}
+
+// AddServer adds a dedicated server with a disabled firewall and no active
+// boot configuration. Empty fields get defaults derived from the number.
+func (m *Mock) AddServer(server Server) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if server.IP == "" {
+		server.IP = "10.0." + strconv.Itoa(server.Number/256%256) + "." + strconv.Itoa(server.Number%256)
+	}
+	if server.IPv6Net == "" {
+		server.IPv6Net = "2a01:4f8:" + strconv.FormatInt(int64(server.Number), 16) + "::"
+	}
+	if server.Name == "" {
+		server.Name = "server" + strconv.Itoa(server.Number)
+	}
+	if server.Product == "" {
+		server.Product = "AX41-NVMe"
+	}
+	if server.DataCenter == "" {
+		server.DataCenter = "FSN1-DC1"
+	}
+	if server.Status == "" {
+		server.Status = "ready"
+	}
+	if server.PaidUntil == "" {
+		server.PaidUntil = "2030-12-31"
+	}
+
+	m.servers = append(m.servers, &serverState{Server: server})
+	m.firewalls[server.Number] = newFirewallState()
+}
+
+// Resets returns the reset types sent for a server, oldest first.
+func (m *Mock) Resets(serverNumber int) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if server := m.findServer(strconv.Itoa(serverNumber)); server != nil {
+		return append([]string(nil), server.resets...)
+	}
+	return nil
+}
+
+// findServer looks a server up by number or main IPv4 address, like Robot
+// does for all {server-id} path parameters.
+func (m *Mock) findServer(id string) *serverState {
+	for _, server := range m.servers {
+		if strconv.Itoa(server.Number) == id || server.IP == id {
+			return server
+		}
+	}
+	return nil
+}
+
+func (m *Mock) serverJSON(server *serverState) map[string]any {
+	return map[string]any{
+		"server_ip":       server.IP,
+		"server_ipv6_net": server.IPv6Net,
+		"server_number":   server.Number,
+		"server_name":     server.Name,
+		"product":         server.Product,
+		"dc":              server.DataCenter,
+		"traffic":         "unlimited",
+		"status":          server.Status,
+		"canceled":        server.Canceled,
+		"paid_until":      server.PaidUntil,
+		"ip":              []string{server.IP},
+		"subnet":          []map[string]any{{"ip": server.IPv6Net, "mask": "64"}},
+		"reset":           true,
+		"rescue":          true,
+		"vnc":             true,
+		"windows":         true,
+		"plesk":           true,
+		"cpanel":          true,
+		"wol":             true,
+		"hot_swap":        false,
+	}
+}
+
+func (m *Mock) routeServers() {
+	m.handle("/", func(w http.ResponseWriter, r *http.Request) {
+		writeNotFound(w, "NOT_FOUND", "Not found")
+	})
+
+	m.handle("GET /server", func(w http.ResponseWriter, r *http.Request) {
+		if len(m.servers) == 0 {
+			writeNotFound(w, "SERVER_NOT_FOUND", "No server found")
+			return
+		}
+		servers := make([]map[string]any, 0, len(m.servers))
+		for _, server := range m.servers {
+			servers = append(servers, map[string]any{"server": m.serverJSON(server)})
+		}
+		writeJSON(w, http.StatusOK, servers)
+	})
+
+	m.handle("GET /server/{id}", func(w http.ResponseWriter, r *http.Request) {
+		server := m.findServer(r.PathValue("id"))
+		if server == nil {
+			writeNotFound(w, "SERVER_NOT_FOUND", "Server %s not found", r.PathValue("id"))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"server": m.serverJSON(server)})
+	})
+
+	m.handle("POST /server/{id}", func(w http.ResponseWriter, r *http.Request) {
+		server := m.findServer(r.PathValue("id"))
+		if server == nil {
+			writeNotFound(w, "SERVER_NOT_FOUND", "Server %s not found", r.PathValue("id"))
+			return
+		}
+		name := r.PostForm.Get("server_name")
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "server_name is required")
+			return
+		}
+		server.Name = name
+		writeJSON(w, http.StatusOK, map[string]any{"server": m.serverJSON(server)})
+	})
+}
+
+var resetTypes = []string{"sw", "hw", "man"}
+
+func (m *Mock) routeReset() {
+	m.handle("GET /reset/{id}", func(w http.ResponseWriter, r *http.Request) {
+		server := m.findServer(r.PathValue("id"))
+		if server == nil {
+			writeNotFound(w, "SERVER_NOT_FOUND", "Server %s not found", r.PathValue("id"))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"reset": map[string]any{
+			"server_ip":        server.IP,
+			"server_ipv6_net":  server.IPv6Net,
+			"server_number":    server.Number,
+			"type":             resetTypes,
+			"operating_status": "not supported",
+		}})
+	})
+
+	m.handle("POST /reset/{id}", func(w http.ResponseWriter, r *http.Request) {
+		server := m.findServer(r.PathValue("id"))
+		if server == nil {
+			writeNotFound(w, "SERVER_NOT_FOUND", "Server %s not found", r.PathValue("id"))
+			return
+		}
+		resetType := r.PostForm.Get("type")
+		valid := false
+		for _, t := range resetTypes {
+			valid = valid || t == resetType
+		}
+		if !valid {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "type must be one of "+strings.Join(resetTypes, ", "))
+			return
+		}
+		server.resets = append(server.resets, resetType)
+		writeJSON(w, http.StatusOK, map[string]any{"reset": map[string]any{
+			"server_ip":     server.IP,
+			"server_number": server.Number,
+			"type":          resetType,
+		}})
+	})
+}
+
+func (m *Mock) routeRDNS() {
+	m.handle("GET /rdns/{ip}", func(w http.ResponseWriter, r *http.Request) {
+		ptr, ok := m.rdns[r.PathValue("ip")]
+		if !ok {
+			writeNotFound(w, "RDNS_NOT_FOUND", "No reverse DNS entry for %s", r.PathValue("ip"))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"rdns": map[string]any{"ip": r.PathValue("ip"), "ptr": ptr}})
+	})
+
+	// PUT creates an entry, POST creates or updates one
+	setRDNS := func(w http.ResponseWriter, r *http.Request) {
+		ip := r.PathValue("ip")
+		if !m.ownsIP(ip) {
+			writeNotFound(w, "IP_NOT_FOUND", "IP %s not found", ip)
+			return
+		}
+		ptr := r.PostForm.Get("ptr")
+		if ptr == "" {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "ptr is required")
+			return
+		}
+		_, exists := m.rdns[ip]
+		if r.Method == http.MethodPut && exists {
+			writeError(w, http.StatusConflict, "RDNS_ALREADY_EXISTS", "Reverse DNS entry already exists")
+			return
+		}
+		m.rdns[ip] = ptr
+
+		status := http.StatusOK
+		if !exists {
+			status = http.StatusCreated
+		}
+		writeJSON(w, status, map[string]any{"rdns": map[string]any{"ip": ip, "ptr": ptr}})
+	}
+	m.handle("PUT /rdns/{ip}", setRDNS)
+	m.handle("POST /rdns/{ip}", setRDNS)
+
+	m.handle("DELETE /rdns/{ip}", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := m.rdns[r.PathValue("ip")]; !ok {
+			writeNotFound(w, "RDNS_NOT_FOUND", "No reverse DNS entry for %s", r.PathValue("ip"))
+			return
+		}
+		delete(m.rdns, r.PathValue("ip"))
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// ownsIP reports whether ip is the main IPv4 address of a server or lies in
+// the IPv6 net of one.
+func (m *Mock) ownsIP(ip string) bool {
+	for _, server := range m.servers {
+		if server.IP == ip || strings.HasPrefix(ip, server.IPv6Net) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+package robotmock
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	minVSwitchVLAN = 4000
+	maxVSwitchVLAN = 4091
+)
+
+// VSwitch is a vSwitch known to the Mock.
+type VSwitch struct {
+	ID       int
+	Name     string
+	VLAN     int
+	Canceled bool
+	// Servers maps attached server numbers to "ready" or "in process".
+	Servers map[int]string
+}
+
+type vSwitchState struct {
+	VSwitch
+	// servers lists attached server numbers in the order they were added
+	servers []int
+	// polls counts down the GET requests until pending servers are ready
+	polls int
+}
+
+// VSwitch returns a vSwitch, or false if it does not exist.
+func (m *Mock) VSwitch(id int) (VSwitch, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	vSwitch := m.findVSwitch(strconv.Itoa(id))
+	if vSwitch == nil {
+		return VSwitch{}, false
+	}
+	result := vSwitch.VSwitch
+	result.Servers = make(map[int]string, len(vSwitch.Servers))
+	for number, status := range vSwitch.Servers {
+		result.Servers[number] = status
+	}
+	return result, true
+}
+
+func (m *Mock) findVSwitch(id string) *vSwitchState {
+	for _, vSwitch := range m.vSwitches {
+		if strconv.Itoa(vSwitch.ID) == id {
+			return vSwitch
+		}
+	}
+	return nil
+}
+
+// poll counts a GET request and marks pending servers as ready once enough
+// polls were seen.
+func (v *vSwitchState) poll() {
+	if !v.inProcess() {
+		return
+	}
+	v.polls--
+	if v.polls > 0 {
+		return
+	}
+	for number := range v.Servers {
+		v.Servers[number] = "ready"
+	}
+}
+
+func (v *vSwitchState) inProcess() bool {
+	for _, status := range v.Servers {
+		if status != "ready" {
+			return true
+		}
+	}
+	return false
+}
+
+func vSwitchSummaryJSON(vSwitch *vSwitchState) map[string]any {
+	return map[string]any{
+		"id":       vSwitch.ID,
+		"name":     vSwitch.Name,
+		"vlan":     vSwitch.VLAN,
+		"canceled": vSwitch.Canceled,
+	}
+}
+
+func (m *Mock) vSwitchJSON(vSwitch *vSwitchState) map[string]any {
+	servers := make([]map[string]any, 0, len(vSwitch.servers))
+	for _, number := range vSwitch.servers {
+		server := m.findServer(strconv.Itoa(number))
+		if server == nil {
+			continue
+		}
+		servers = append(servers, map[string]any{
+			"server_number":   server.Number,
+			"server_ip":       server.IP,
+			"server_ipv6_net": server.IPv6Net,
+			"status":          vSwitch.Servers[number],
+		})
+	}
+	result := vSwitchSummaryJSON(vSwitch)
+	result["server"] = servers
+	result["subnet"] = []any{}
+	result["cloud_network"] = []any{}
+	return result
+}
+
+// vSwitchServers reads the servers of a request. Robot documents server[],
+// but also accepts a repeated server field.
+func (m *Mock) vSwitchServers(w http.ResponseWriter, r *http.Request) ([]int, bool) {
+	ids := append(r.PostForm["server[]"], r.PostForm["server"]...)
+	if len(ids) == 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "server is required")
+		return nil, false
+	}
+	numbers := make([]int, 0, len(ids))
+	for _, id := range ids {
+		server := m.findServer(id)
+		if server == nil {
+			writeNotFound(w, "SERVER_NOT_FOUND", "Server %s not found", id)
+			return nil, false
+		}
+		numbers = append(numbers, server.Number)
+	}
+	return numbers, true
+}
+
+// vSwitchInput validates the name and VLAN of a create or update request.
+func (m *Mock) vSwitchInput(w http.ResponseWriter, r *http.Request, id int) (string, int, bool) {
+	name := r.PostForm.Get("name")
+	vlan, err := strconv.Atoi(r.PostForm.Get("vlan"))
+	if name == "" || err != nil || vlan < minVSwitchVLAN || vlan > maxVSwitchVLAN {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "name and a vlan between 4000 and 4091 are required")
+		return "", 0, false
+	}
+	for _, other := range m.vSwitches {
+		if other.ID != id && other.VLAN == vlan {
+			writeError(w, http.StatusConflict, "VSWITCH_VLAN_NOT_UNIQUE", "VLAN "+strconv.Itoa(vlan)+" is already in use")
+			return "", 0, false
+		}
+	}
+	return name, vlan, true
+}
+
+func (m *Mock) routeVSwitch() {
+	m.handle("GET /vswitch", func(w http.ResponseWriter, r *http.Request) {
+		vSwitches := make([]map[string]any, 0, len(m.vSwitches))
+		for _, vSwitch := range m.vSwitches {
+			vSwitches = append(vSwitches, vSwitchSummaryJSON(vSwitch))
+		}
+		writeJSON(w, http.StatusOK, vSwitches)
+	})
+
+	m.handle("POST /vswitch", func(w http.ResponseWriter, r *http.Request) {
+		name, vlan, ok := m.vSwitchInput(w, r, 0)
+		if !ok {
+			return
+		}
+		vSwitch := &vSwitchState{VSwitch: VSwitch{ID: m.nextVSwitch, Name: name, VLAN: vlan, Servers: map[int]string{}}}
+		m.nextVSwitch++
+		m.vSwitches = append(m.vSwitches, vSwitch)
+		writeJSON(w, http.StatusCreated, m.vSwitchJSON(vSwitch))
+	})
+
+	m.handle("GET /vswitch/{id}", func(w http.ResponseWriter, r *http.Request) {
+		vSwitch := m.findVSwitch(r.PathValue("id"))
+		if vSwitch == nil {
+			writeNotFound(w, "NOT_FOUND", "vSwitch %s not found", r.PathValue("id"))
+			return
+		}
+		vSwitch.poll()
+		writeJSON(w, http.StatusOK, m.vSwitchJSON(vSwitch))
+	})
+
+	m.handle("POST /vswitch/{id}", func(w http.ResponseWriter, r *http.Request) {
+		vSwitch := m.findVSwitch(r.PathValue("id"))
+		if vSwitch == nil {
+			writeNotFound(w, "NOT_FOUND", "vSwitch %s not found", r.PathValue("id"))
+			return
+		}
+		name, vlan, ok := m.vSwitchInput(w, r, vSwitch.ID)
+		if !ok {
+			return
+		}
+		vSwitch.Name, vSwitch.VLAN = name, vlan
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	m.handle("DELETE /vswitch/{id}", func(w http.ResponseWriter, r *http.Request) {
+		vSwitch := m.findVSwitch(r.PathValue("id"))
+		if vSwitch == nil {
+			writeNotFound(w, "NOT_FOUND", "vSwitch %s not found", r.PathValue("id"))
+			return
+		}
+		cancellationDate := r.PostForm.Get("cancellation_date")
+		if cancellationDate == "now" {
+			m.vSwitches = slices.DeleteFunc(m.vSwitches, func(other *vSwitchState) bool { return other == vSwitch })
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		date, err := time.Parse(time.DateOnly, cancellationDate)
+		if err != nil || date.Before(m.config.Now().Truncate(24*time.Hour)) {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "cancellation_date must be now or a future date")
+			return
+		}
+		vSwitch.Canceled = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	m.handle("POST /vswitch/{id}/server", func(w http.ResponseWriter, r *http.Request) {
+		vSwitch := m.findVSwitch(r.PathValue("id"))
+		if vSwitch == nil {
+			writeNotFound(w, "NOT_FOUND", "vSwitch %s not found", r.PathValue("id"))
+			return
+		}
+		if vSwitch.inProcess() {
+			writeError(w, http.StatusConflict, "VSWITCH_IN_PROCESS", "There is an update of the vSwitch in process")
+			return
+		}
+		numbers, ok := m.vSwitchServers(w, r)
+		if !ok {
+			return
+		}
+		for _, number := range numbers {
+			if _, attached := vSwitch.Servers[number]; attached {
+				writeError(w, http.StatusConflict, "VSWITCH_SERVER_ALREADY_ADDED", "Server "+strconv.Itoa(number)+" is already attached")
+				return
+			}
+		}
+
+		status := "in process"
+		if m.config.SettlePolls <= 0 {
+			status = "ready"
+		}
+		for _, number := range numbers {
+			vSwitch.Servers[number] = status
+			vSwitch.servers = append(vSwitch.servers, number)
+		}
+		vSwitch.polls = m.config.SettlePolls
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	m.handle("DELETE /vswitch/{id}/server", func(w http.ResponseWriter, r *http.Request) {
+		vSwitch := m.findVSwitch(r.PathValue("id"))
+		if vSwitch == nil {
+			writeNotFound(w, "NOT_FOUND", "vSwitch %s not found", r.PathValue("id"))
+			return
+		}
+		if vSwitch.inProcess() {
+			writeError(w, http.StatusConflict, "VSWITCH_IN_PROCESS", "There is an update of the vSwitch in process")
+			return
+		}
+		numbers, ok := m.vSwitchServers(w, r)
+		if !ok {
+			return
+		}
+		for _, number := range numbers {
+			if _, attached := vSwitch.Servers[number]; !attached {
+				writeNotFound(w, "SERVER_NOT_FOUND", "Server %d is not attached", number)
+				return
+			}
+		}
+		for _, number := range numbers {
+			delete(vSwitch.Servers, number)
+			vSwitch.servers = slices.DeleteFunc(vSwitch.servers, func(other int) bool { return other == number })
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
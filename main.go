@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
 	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot"
 )
 
@@ -23,11 +25,18 @@ func main() {
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	opts := &plugin.ServeOpts{
-		Debug:        debug,
-		ProviderAddr: "registry.terraform.io/strng-solutions/hetzner-robot",
-		ProviderFunc: hetznerrobot.Provider,
+	providerServer, err := hetznerrobot.ProviderServer(context.Background())
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	plugin.Serve(opts)
+	var opts []tf6server.ServeOpt
+	if debug {
+		opts = append(opts, tf6server.WithManagedDebug())
+	}
+
+	err = tf6server.Serve("registry.terraform.io/strng-solutions/hetzner-robot", providerServer, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
 }